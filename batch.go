@@ -0,0 +1,223 @@
+package gometawebhooks
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+)
+
+// ItemError identifies a change or messaging item which failed to be handled
+type ItemError struct {
+	EntryId string
+	// position of the item within the entry changes or messaging
+	Index int
+	Kind  string
+	// position of the WhatsApp message or status within the change value, zero for other items
+	Item int
+	Err  error
+
+	entry int
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("'%s' %d of entry '%s': %v", e.Kind, e.Index, e.EntryId, e.Err)
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// DispatchError is returned by Handle in continue on error mode, listing every item which failed to be handled
+type DispatchError struct {
+	Errors []*ItemError
+	// error which stopped dispatching the remaining items, e.g. the ctx error, nil when every item was dispatched
+	Err error
+}
+
+func (e *DispatchError) Error() string {
+	errs := make([]string, 0, len(e.Errors)+1)
+	for _, err := range e.Errors {
+		errs = append(errs, err.Error())
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err.Error())
+	}
+	return strings.Join(errs, "\n")
+}
+
+// Unwraps every item error and Err, as errors.Join does
+func (e *DispatchError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors)+1)
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// Dispatch state of a single Handle call
+type batch struct {
+	continueOnError bool
+	recoverPanics   bool
+
+	// bound dispatched items of this call and across calls, nil when unlimited
+	semaphore       *semaphore.Weighted
+	sharedSemaphore *semaphore.Weighted
+
+	mu     sync.Mutex
+	errors []*ItemError
+
+	// first panic recovered while dispatching, re-panicked by Handle unless recovering panics
+	panicked *HandlerPanicError
+	// set once Handle returns, handlers outliving their timeout may still panic
+	done bool
+}
+
+func (h Webhooks) newBatch() *batch {
+	b := &batch{
+		continueOnError: h.continueOnError,
+		recoverPanics:   h.recoverPanics,
+		sharedSemaphore: h.sharedSemaphore,
+	}
+	if h.maxConcurrency > 0 {
+		b.semaphore = semaphore.NewWeighted(h.maxConcurrency)
+	}
+	return b
+}
+
+// Returns a group which cancels siblings on the first error, unless continuing on error
+func (b *batch) group(ctx context.Context) (*errgroup.Group, context.Context) {
+	if b.continueOnError {
+		return &errgroup.Group{}, ctx
+	}
+	return errgroup.WithContext(ctx)
+}
+
+// Permit to dispatch an item, released once its dispatch and a handler outliving its timeout both return
+type permit struct {
+	b    *batch
+	refs atomic.Int32
+}
+
+// Holds the permit until the matching release
+func (p *permit) hold() {
+	p.refs.Add(1)
+}
+
+func (p *permit) release() {
+	if p.refs.Add(-1) == 0 {
+		p.b.release()
+	}
+}
+
+// Blocks until an item may be dispatched, every returned permit must be released
+func (b *batch) acquire(ctx context.Context) (*permit, error) {
+	if b.semaphore != nil {
+		if err := b.semaphore.Acquire(ctx, 1); err != nil {
+			return nil, context.Cause(ctx)
+		}
+	}
+	if b.sharedSemaphore != nil {
+		if err := b.sharedSemaphore.Acquire(ctx, 1); err != nil {
+			if b.semaphore != nil {
+				b.semaphore.Release(1)
+			}
+			return nil, context.Cause(ctx)
+		}
+	}
+
+	p := &permit{b: b}
+	p.refs.Store(1)
+	return p, nil
+}
+
+func (b *batch) release() {
+	if b.sharedSemaphore != nil {
+		b.sharedSemaphore.Release(1)
+	}
+	if b.semaphore != nil {
+		b.semaphore.Release(1)
+	}
+}
+
+// Records err of an item when continuing on error, otherwise returns it to cancel siblings
+func (b *batch) fail(position int, entry Entry, index int, item interface{}, err error) error {
+	return b.failAt(position, entry, index, 0, item, err)
+}
+
+// Records err of a WhatsApp message or status at sub of the change at index, see fail
+func (b *batch) failAt(position int, entry Entry, index, sub int, item interface{}, err error) error {
+	if err == nil || !b.continueOnError {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.errors = append(b.errors, &ItemError{
+		EntryId: entry.Id,
+		Index:   index,
+		Kind:    itemKind(item),
+		Item:    sub,
+		Err:     err,
+		entry:   position,
+	})
+	return nil
+}
+
+// Returns the recorded item errors in entry and item order along err which stopped dispatching, if any
+func (b *batch) err(err error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.errors) == 0 {
+		return err
+	}
+
+	errs := slices.Clone(b.errors)
+	slices.SortFunc(errs, func(a, b *ItemError) int {
+		return cmp.Or(
+			cmp.Compare(a.entry, b.entry),
+			cmp.Compare(a.Index, b.Index),
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Item, b.Item),
+		)
+	})
+	return &DispatchError{Errors: errs, Err: err}
+}
+
+// Records a panic recovered while dispatching unless recovering panics, re-panics it on the calling goroutine
+// when Handle already returned, e.g. from a handler outliving its timeout
+func (b *batch) recovered(err *HandlerPanicError) {
+	if b.recoverPanics {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.done {
+		panic(err)
+	}
+	if b.panicked == nil {
+		b.panicked = err
+	}
+}
+
+// Marks Handle as returned, returns the first panic recovered while dispatching unless recovering panics
+func (b *batch) finish() *HandlerPanicError {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.done = true
+	return b.panicked
+}
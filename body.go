@@ -0,0 +1,78 @@
+package gometawebhooks
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	ErrPayloadTooLarge            = errors.New("payload too large")
+	ErrUnsupportedContentEncoding = errors.New("unsupported content encoding")
+	ErrDecompressingPayload       = errors.New("decompressing payload")
+)
+
+// Returns the limit set by Options.MaxBodyBytes, zero when payloads are read unbounded
+func (hooks Webhooks) MaxBodyBytes() int64 {
+	return hooks.maxBodyBytes
+}
+
+// Reads the payload of body decoded according to contentEncoding, bounded by Options.MaxBodyBytes
+func (hooks Webhooks) ReadPayload(body io.Reader, contentEncoding string) ([]byte, error) {
+	return ReadPayload(body, contentEncoding, hooks.maxBodyBytes)
+}
+
+// Reads the payload of body decoded according to contentEncoding, identity or gzip, returns ErrPayloadTooLarge
+// when either body or the decoded payload exceed maxBytes, a maxBytes of zero or less reads unbounded.
+func ReadPayload(body io.Reader, contentEncoding string, maxBytes int64) ([]byte, error) {
+	switch encoding := strings.ToLower(strings.TrimSpace(contentEncoding)); encoding {
+	case "", "identity":
+		return io.ReadAll(limitReader(body, maxBytes))
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(limitReader(body, maxBytes))
+		if err != nil {
+			return nil, decompressErr(err)
+		}
+		defer zr.Close()
+
+		payload, err := io.ReadAll(limitReader(zr, maxBytes))
+		if err != nil {
+			return nil, decompressErr(err)
+		}
+		return payload, nil
+	default:
+		return nil, fmt.Errorf("'%s': %w", contentEncoding, ErrUnsupportedContentEncoding)
+	}
+}
+
+func limitReader(r io.Reader, maxBytes int64) io.Reader {
+	if maxBytes <= 0 {
+		return r
+	}
+	return &limitedReader{r: r, maxBytes: maxBytes}
+}
+
+// Errors once more than maxBytes are read
+type limitedReader struct {
+	r        io.Reader
+	maxBytes int64
+	n        int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	if l.n > l.maxBytes {
+		return n, fmt.Errorf("exceeds %d bytes: %w", l.maxBytes, ErrPayloadTooLarge)
+	}
+	return n, err
+}
+
+func decompressErr(err error) error {
+	if errors.Is(err, ErrPayloadTooLarge) {
+		return err
+	}
+	return wrapErr(err, ErrDecompressingPayload)
+}
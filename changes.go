@@ -5,14 +5,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-
-	"golang.org/x/sync/errgroup"
 )
 
 var (
 	ErrChangesFieldNotImplemented              = errors.New("changes field not implemented")
 	ErrInstagramMentionHandlerNotDefined       = errors.New("instagram mentions handler not defined")
 	ErrInstagramStoryInsightsHandlerNotDefined = errors.New("instagram story insights handler not defined")
+	ErrInstagramCommentHandlerNotDefined       = errors.New("instagram comments handler not defined")
+	ErrInstagramLiveCommentHandlerNotDefined   = errors.New("instagram live comments handler not defined")
 )
 
 type Mention struct {
@@ -30,55 +30,200 @@ type StoryInsights struct {
 	Impressions int    `json:"impressions,omitempty"`
 }
 
+type CommentFrom struct {
+	Id       string `json:"id,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+type CommentMedia struct {
+	Id               string `json:"id,omitempty"`
+	MediaProductType string `json:"media_product_type,omitempty"`
+}
+
+// https://developers.facebook.com/docs/instagram-platform/webhooks/examples#comments
+type Comment struct {
+	Id       string       `json:"id,omitempty"`
+	Text     string       `json:"text,omitempty"`
+	From     CommentFrom  `json:"from,omitempty"`
+	Media    CommentMedia `json:"media,omitempty"`
+	ParentId string       `json:"parent_id,omitempty"`
+}
+
+// https://developers.facebook.com/docs/instagram-platform/webhooks/examples#live-comments
+type LiveComment Comment
+
 type Change struct {
 	Field string      `json:"field,omitempty"`
 	Value interface{} `json:"value,omitempty"`
+
+	// undecoded value of unsupported objects and fields, see Options.RawChangeHandler
+	Raw json.RawMessage `json:"-"`
+}
+
+// Marshals the decoded value, or the undecoded value of unsupported fields, keeping the original fields
+func (c Change) MarshalJSON() ([]byte, error) {
+	type Alias Change
+	change := Alias(c)
+	if c.Raw != nil {
+		change.Value = c.Raw
+	}
+	return json.Marshal(change)
 }
 
 func (c *Change) UnmarshalJSON(data []byte) error {
-	var raw map[string]json.RawMessage
+	var raw struct {
+		Field string          `json:"field"`
+		Value json.RawMessage `json:"value"`
+	}
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return err
 	}
 
-	if fieldRaw, ok := raw["field"]; ok {
-		if err := json.Unmarshal(fieldRaw, &c.Field); err != nil {
+	change := Change{Field: raw.Field}
+	if raw.Value != nil {
+		value, err := anyChangeValue(raw.Field, raw.Value)
+		if err != nil {
 			return err
 		}
-	}
 
-	if valueRaw, ok := raw["value"]; ok {
-		switch c.Field {
-		case "mentions":
-			var value Mention
-			if err := json.Unmarshal(valueRaw, &value); err != nil {
-				return err
-			}
-			c.Value = value
-		case "story_insights":
-			var value StoryInsights
-			if err := json.Unmarshal(valueRaw, &value); err != nil {
-				return err
-			}
-			c.Value = value
-		default:
-			return fmt.Errorf("'%s': %w", c.Field, ErrChangesFieldNotImplemented)
+		// @note unsupported fields are only kept undecoded by Webhooks.ParsePayload, see Options.RawChangeHandler
+		if value == nil {
+			return fmt.Errorf("'%s': %w", raw.Field, ErrChangesFieldNotImplemented)
 		}
+		change.Value = value
 	}
 
+	*c = change
 	return nil
 }
 
-func (hooks Webhooks) changes(ctx context.Context, object Object, entry Entry) error {
+// Decoders of built-in fields, keyed by object and field like registered fields, see Options.ChangeField
+var changeValues = map[changeField]valueDecoder{
+	{Instagram, "mentions"}:       valueOf[Mention]{},
+	{Instagram, "story_insights"}: valueOf[StoryInsights]{},
+	{Instagram, "comments"}:       valueOf[Comment]{},
+	{Instagram, "live_comments"}:  valueOf[LiveComment]{},
+
+	{WhatsAppBusinessAccount, "messages"}: valueOf[WhatsAppMessages]{},
+}
+
+// Decodes the value of a built-in field from undecoded JSON or from the decoded payload tree
+type valueDecoder interface {
+	decode(raw json.RawMessage) (interface{}, error)
+	assign(node interface{}) (interface{}, error)
+}
+
+type valueOf[T any] struct{}
+
+func (valueOf[T]) decode(raw json.RawMessage) (interface{}, error) {
+	var value T
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (valueOf[T]) assign(node interface{}) (interface{}, error) {
+	var value T
+	if err := assignTree(node, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Decodes the value of built-in fields of any object, as it is unknown when unmarshalling a Change directly
+func anyChangeValue(field string, raw json.RawMessage) (interface{}, error) {
+	for key, value := range changeValues {
+		if key.field == field {
+			return value.decode(raw)
+		}
+	}
+	return nil, nil
+}
+
+// Change of a payload, decoded against registered and raw handlers once its object is known
+type payloadChange struct {
+	Field string      `json:"field"`
+	Value payloadItem `json:"value"`
+}
+
+// Resolves the change against registered and raw handlers, ensures its field is supported,
+// undecoded returns the undecoded value for registered and raw handlers
+func (hooks Webhooks) parseChange(object Object, raw payloadChange, undecoded func() (json.RawMessage, error)) (Change, error) {
+	change := Change{Field: raw.Field}
+	if raw.Value.node == nil {
+		if !object.supported() {
+			return change, fmt.Errorf("'%s': %w", object, ErrObjectNotSupported)
+		}
+		return change, nil
+	}
+
+	if fn, ok := hooks.changeFields[changeField{object, change.Field}]; ok {
+		value, err := undecoded()
+		if err != nil {
+			return change, err
+		}
+		if change.Value, err = fn.DecodeChange(object, change.Field, value); err != nil {
+			return change, err
+		}
+		return change, nil
+	}
+
+	decoder, ok := changeValues[changeField{object, change.Field}]
+
+	if hooks.rawChangeHandler != nil && (!ok || !object.supported()) {
+		value, err := undecoded()
+		if err != nil {
+			return change, err
+		}
+		change.Raw = value
+		return change, nil
+	}
+
+	if !object.supported() {
+		return change, fmt.Errorf("'%s': %w", object, ErrObjectNotSupported)
+	}
+
+	if !ok {
+		return change, fmt.Errorf("'%s': %w", change.Field, ErrChangesFieldNotImplemented)
+	}
+
+	value, err := decoder.assign(raw.Value.node)
+	if err != nil {
+		return change, err
+	}
+	change.Value = value
+	return change, nil
+}
+
+func (hooks Webhooks) changes(ctx context.Context, b *batch, position int, object Object, entry Entry) error {
 	if len(entry.Changes) == 0 {
 		return nil
 	}
 
-	g, ctx := errgroup.WithContext(ctx)
+	g, ctx := b.group(ctx)
 	g.SetLimit(len(entry.Changes))
-	for _, change := range entry.Changes {
+	for i, change := range entry.Changes {
+		if value, ok := hooks.whatsAppValue(object, change); ok {
+			g.Go(func() error {
+				return hooks.whatsAppMessages(ctx, b, position, object, entry, i, change, value)
+			})
+			continue
+		}
+
+		p, err := b.acquire(ctx)
+		if err != nil {
+			g.Go(func() error { return err })
+			break
+		}
+
 		g.Go(func() error {
-			return hooks.change(ctx, object, entry, change)
+			defer p.release()
+
+			err := hooks.dispatch(ctx, p, object, entry, change, func(ctx context.Context) error {
+				return hooks.change(ctx, object, entry, change)
+			})
+			return b.fail(position, entry, i, change, err)
 		})
 	}
 
@@ -86,6 +231,14 @@ func (hooks Webhooks) changes(ctx context.Context, object Object, entry Entry) e
 }
 
 func (h Webhooks) change(ctx context.Context, object Object, entry Entry, change Change) error {
+	if fn, ok := h.changeFields[changeField{object, change.Field}]; ok {
+		return fn.HandleChange(ctx, object, entry, change)
+	}
+
+	if change.Raw != nil && h.rawChangeHandler != nil {
+		return h.rawChangeHandler.RawChange(ctx, object, entry, change)
+	}
+
 	switch value := change.Value.(type) {
 	case Mention:
 		if h.instagramMentionHandler == nil {
@@ -97,8 +250,18 @@ func (h Webhooks) change(ctx context.Context, object Object, entry Entry, change
 			return ErrInstagramStoryInsightsHandlerNotDefined
 		}
 		return h.instagramStoryInsightsHandler.InstagramStoryInsights(ctx, object, entry, value)
+	case Comment:
+		if h.instagramCommentHandler == nil {
+			return ErrInstagramCommentHandlerNotDefined
+		}
+		return h.instagramCommentHandler.InstagramComment(ctx, object, entry, value)
+	case LiveComment:
+		if h.instagramLiveCommentHandler == nil {
+			return ErrInstagramLiveCommentHandlerNotDefined
+		}
+		return h.instagramLiveCommentHandler.InstagramLiveComment(ctx, object, entry, value)
 	default:
-		// @note should not be hit cause Unmarshall ensures field is supported
+		// @note should not be hit cause ParsePayload ensures field is supported
 		return fmt.Errorf("'%s': %w", change.Field, ErrChangesFieldNotImplemented)
 	}
 }
@@ -0,0 +1,88 @@
+package gometawebhooks
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"slices"
+)
+
+// Identifies a conversation by its recipient and sender
+type conversationKey struct {
+	recipient string
+	sender    string
+}
+
+// A messaging item of a conversation and its position within the event
+type conversationItem struct {
+	position  int
+	entry     Entry
+	index     int
+	timestamp int64
+}
+
+// Dispatches messaging items of all entries grouped by conversation, each conversation sequentially in timestamp order
+func (h Webhooks) conversations(ctx context.Context, b *batch, object Object, entries []Entry) error {
+	var keys []conversationKey
+	conversations := make(map[conversationKey][]conversationItem)
+	for position, entry := range entries {
+		for index, messaging := range entry.Messaging {
+			header := messaging.header()
+			key := conversationKey{header.Recipient.Id, header.Sender.Id}
+			if _, ok := conversations[key]; !ok {
+				keys = append(keys, key)
+			}
+			conversations[key] = append(conversations[key], conversationItem{position, entry, index, header.Timestamp})
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	g, ctx := b.group(ctx)
+	g.SetLimit(len(keys))
+	for _, key := range keys {
+		items := conversations[key]
+		slices.SortStableFunc(items, func(a, b conversationItem) int {
+			return cmp.Compare(a.timestamp, b.timestamp)
+		})
+
+		g.Go(func() error {
+			for _, item := range items {
+				select {
+				case <-ctx.Done():
+					return context.Cause(ctx)
+				default:
+				}
+
+				p, err := b.acquire(ctx)
+				if err != nil {
+					return err
+				}
+
+				err = h.messagingItem(ctx, b, p, item.position, object, item.entry, item.index)
+				p.release()
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
+// Returns the header of the messaging item, decoded from the raw payload of unknown items
+func (t Messaging) header() MessagingHeader {
+	if header, ok := t.Type.(messagingHeader); ok {
+		return header.header()
+	}
+
+	var header MessagingHeader
+	if t.Raw != nil {
+		_ = json.Unmarshal(t.Raw, &header)
+	}
+	return header
+}
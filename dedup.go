@@ -0,0 +1,277 @@
+package gometawebhooks
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	ErrDeduplicating = errors.New("deduplicating")
+)
+
+// DedupStore records keys of handled changes and messaging, implementations must be safe for concurrent use
+type DedupStore interface {
+	// Records key and reports whether it was already recorded
+	Seen(ctx context.Context, key string) (bool, error)
+	// Removes key so that items failing to be handled are handled again when redelivered
+	Forget(ctx context.Context, key string) error
+}
+
+// Optionally implemented by a DedupStore to record keys as pending until their items are handled, Seen should
+// then wait for pending keys to be committed or forgotten, so that redeliveries racing a failing item aren't skipped
+type DedupCommitter interface {
+	// Records key, previously recorded as pending by Seen, as handled
+	Commit(ctx context.Context, key string) error
+}
+
+var (
+	_ DedupStore     = (*MemoryDedupStore)(nil)
+	_ DedupCommitter = (*MemoryDedupStore)(nil)
+)
+
+type dedupEntry struct {
+	key     string
+	expires time.Time
+	// closed once the key is committed or forgotten, nil when committed
+	pending chan struct{}
+}
+
+// In-memory DedupStore expiring keys after a TTL and evicting the least recently seen keys over capacity,
+// keys pending in-flight aren't evicted until committed
+type MemoryDedupStore struct {
+	ttl      time.Duration
+	capacity int
+
+	mu    sync.Mutex
+	order *list.List
+	keys  map[string]*list.Element
+}
+
+// Creates a MemoryDedupStore, a ttl or capacity of zero disables expiry or eviction
+func NewMemoryDedupStore(ttl time.Duration, capacity int) *MemoryDedupStore {
+	return &MemoryDedupStore{
+		ttl:      ttl,
+		capacity: capacity,
+		order:    list.New(),
+		keys:     make(map[string]*list.Element),
+	}
+}
+
+// Records key as pending, waits for keys pending in-flight to be committed, forgotten or expire until ctx is done
+func (s *MemoryDedupStore) Seen(ctx context.Context, key string) (bool, error) {
+	for {
+		seen, pending, expires := s.seen(key)
+		if pending == nil {
+			return seen, nil
+		}
+
+		if err := waitPending(ctx, pending, expires); err != nil {
+			return false, err
+		}
+	}
+}
+
+// Waits for pending to be closed or expires, a zero expires never does
+func waitPending(ctx context.Context, pending chan struct{}, expires time.Time) error {
+	var expired <-chan time.Time
+	if !expires.IsZero() {
+		timer := time.NewTimer(time.Until(expires))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case <-pending:
+		return nil
+	case <-expired:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// Records key as pending, or returns the channel and expiry of a key already pending
+func (s *MemoryDedupStore) seen(key string) (bool, chan struct{}, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var expires time.Time
+	if s.ttl > 0 {
+		expires = now.Add(s.ttl)
+	}
+
+	if el, ok := s.keys[key]; ok {
+		entry := el.Value.(*dedupEntry)
+		expired := !entry.expires.IsZero() && now.After(entry.expires)
+		if !expired && entry.pending != nil {
+			return false, entry.pending, entry.expires
+		}
+
+		entry.expires = expires
+		if expired {
+			if entry.pending != nil {
+				close(entry.pending)
+			}
+			entry.pending = make(chan struct{})
+		}
+		s.order.MoveToFront(el)
+		return !expired, nil, time.Time{}
+	}
+
+	s.keys[key] = s.order.PushFront(&dedupEntry{key, expires, make(chan struct{})})
+
+	// keys pending in-flight are only evicted once committed or expired, so redeliveries keep waiting on them
+	for el := s.order.Back(); el != nil; {
+		entry := el.Value.(*dedupEntry)
+		expired := !entry.expires.IsZero() && !now.Before(entry.expires)
+		if !expired && (s.capacity <= 0 || s.order.Len() <= s.capacity) {
+			break
+		}
+
+		prev := el.Prev()
+		if expired || entry.pending == nil {
+			s.remove(el)
+		}
+		el = prev
+	}
+
+	return false, nil, time.Time{}
+}
+
+func (s *MemoryDedupStore) Commit(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.keys[key]; ok {
+		entry := el.Value.(*dedupEntry)
+		if entry.pending != nil {
+			close(entry.pending)
+			entry.pending = nil
+		}
+	}
+	return nil
+}
+
+func (s *MemoryDedupStore) Forget(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.keys[key]; ok {
+		s.remove(el)
+	}
+	return nil
+}
+
+// Removes the entry, waking up Seen calls waiting on it when pending
+func (s *MemoryDedupStore) remove(el *list.Element) {
+	entry := el.Value.(*dedupEntry)
+	if entry.pending != nil {
+		close(entry.pending)
+	}
+	s.order.Remove(el)
+	delete(s.keys, entry.key)
+}
+
+// Skips fn when item was already handled, keyed by message or postback mid, WhatsApp message id or status,
+// or a hash of entry id, time and item
+func (h Webhooks) deduplicate(ctx context.Context, object Object, entry Entry, item interface{}, fn func() error) error {
+	if h.dedupStore == nil {
+		return fn()
+	}
+
+	key, err := dedupKey(object, entry, item)
+	if err != nil {
+		return wrapErr(err, ErrDeduplicating)
+	}
+
+	seen, err := h.dedupStore.Seen(ctx, key)
+	if err != nil {
+		return wrapErr(err, ErrDeduplicating)
+	}
+
+	if seen {
+		if logger := h.log(); logger.Enabled(ctx, slog.LevelDebug) {
+			logger.LogAttrs(ctx, slog.LevelDebug, "skipped duplicate", append(h.itemAttrs(object, entry, item), slog.String("key", key))...)
+		}
+		return nil
+	}
+
+	// forgotten when fn fails or panics
+	handled := false
+	defer func() {
+		if !handled {
+			_ = h.dedupStore.Forget(context.WithoutCancel(ctx), key)
+		}
+	}()
+
+	if err := fn(); err != nil {
+		return err
+	}
+	handled = true
+
+	if committer, ok := h.dedupStore.(DedupCommitter); ok {
+		_ = committer.Commit(context.WithoutCancel(ctx), key)
+	}
+	return nil
+}
+
+func dedupKey(object Object, entry Entry, item interface{}) (string, error) {
+	var value interface{}
+	switch item := item.(type) {
+	case Messaging:
+		switch t := item.Type.(type) {
+		case MessagingMessage:
+			if t.Message.Id != "" {
+				return object.String() + ":mid:" + t.Message.Id, nil
+			}
+		case MessagingPostback:
+			if t.Postback.Id != "" {
+				return object.String() + ":postback:" + t.Postback.Id, nil
+			}
+		}
+
+		value = item.Type
+		if item.Raw != nil {
+			value = item.Raw
+		}
+	case WhatsAppMessage:
+		if item.Id != "" {
+			return object.String() + ":wamid:" + item.Id, nil
+		}
+		value = item
+	case WhatsAppStatus:
+		// statuses of a message progress, e.g. sent, delivered and read
+		if item.Id != "" && item.Status != "" {
+			return object.String() + ":status:" + item.Id + ":" + item.Status, nil
+		}
+		value = item
+	case Change:
+		value = item
+		if item.Raw != nil {
+			value = struct {
+				Field string          `json:"field"`
+				Raw   json.RawMessage `json:"raw"`
+			}{item.Field, item.Raw}
+		}
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(entry.Id))
+	hash.Write([]byte(strconv.FormatInt(entry.Time, 10)))
+	hash.Write(b)
+	return object.String() + ":hash:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,24 @@
+package gometawebhooks
+
+import (
+	"context"
+	"time"
+)
+
+// Invokes fn for a change or messaging item through middlewares, skipping duplicates, recovering panics,
+// bounding it by the handler timeout and logging the outcome, p is held until fn returns
+func (h Webhooks) dispatch(ctx context.Context, p *permit, object Object, entry Entry, item interface{}, fn func(context.Context) error) error {
+	kind := itemKind(item)
+
+	return h.deduplicate(ctx, object, entry, item, func() (err error) {
+		defer func(start time.Time) {
+			h.logDispatch(ctx, object, entry, item, time.Since(start), err)
+		}(time.Now())
+
+		return h.withTimeout(ctx, p, object, entry, kind, func(ctx context.Context) (err error) {
+			defer recoverPanic(p.b, object, entry, kind, &err)
+
+			return h.chain(fn)(ctx, object, entry, dispatchItem(item))
+		})
+	})
+}
@@ -5,8 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-
-	"golang.org/x/sync/errgroup"
 )
 
 var (
@@ -31,7 +29,8 @@ func (t *Entry) UnmarshalJSON(b []byte) error {
 		return fmt.Errorf("missing 'id' field: %w", ErrParsingEntry)
 	}
 
-	if entry.Time == 0 {
+	// the object is unknown here, only whatsapp_business_account changes may omit time
+	if entry.Time == 0 && !whatsAppChanges(entry.Changes) {
 		return fmt.Errorf("missing 'time' field: %w", ErrParsingEntry)
 	}
 
@@ -39,8 +38,69 @@ func (t *Entry) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-func (h Webhooks) entry(ctx context.Context, object Object, entry Entry) error {
-	g, ctx := errgroup.WithContext(ctx)
+// Reports whether the entry only has whatsapp_business_account changes
+func whatsAppChanges(changes []Change) bool {
+	if len(changes) == 0 {
+		return false
+	}
+	for _, change := range changes {
+		if _, ok := change.Value.(WhatsAppMessages); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Entry of a payload, its changes and messaging are decoded once its object is known
+type payloadEntry struct {
+	Id        string          `json:"id"`
+	Time      int64           `json:"time"`
+	Messaging []payloadItem   `json:"messaging"`
+	Changes   []payloadChange `json:"changes"`
+}
+
+// Builds the entry at position of the decoded payload
+func (hooks Webhooks) parseEntry(d *decodedPayload, position int, object Object, raw payloadEntry) (Entry, error) {
+	entry := Entry{Id: raw.Id, Time: raw.Time}
+
+	if raw.Messaging != nil {
+		entry.Messaging = make([]Messaging, len(raw.Messaging))
+		for i, item := range raw.Messaging {
+			var err error
+			if entry.Messaging[i], err = hooks.parseMessaging(object, item.node, func() (json.RawMessage, error) {
+				return d.rawMessaging(position, i)
+			}); err != nil {
+				return entry, err
+			}
+		}
+	}
+
+	if raw.Changes != nil {
+		entry.Changes = make([]Change, len(raw.Changes))
+		for i, item := range raw.Changes {
+			var err error
+			if entry.Changes[i], err = hooks.parseChange(object, item, func() (json.RawMessage, error) {
+				return d.rawChange(position, i)
+			}); err != nil {
+				return entry, err
+			}
+		}
+	}
+
+	if entry.Id == "" {
+		return entry, fmt.Errorf("missing 'id' field: %w", ErrParsingEntry)
+	}
+
+	// whatsapp_business_account entries omit time
+	if entry.Time == 0 && object != WhatsAppBusinessAccount {
+		return entry, fmt.Errorf("missing 'time' field: %w", ErrParsingEntry)
+	}
+
+	return entry, nil
+}
+
+func (h Webhooks) entry(ctx context.Context, b *batch, position int, object Object, entry Entry) error {
+	g, ctx := b.group(ctx)
 
 	g.SetLimit(2)
 
@@ -49,16 +109,21 @@ func (h Webhooks) entry(ctx context.Context, object Object, entry Entry) error {
 		case <-ctx.Done():
 			return context.Cause(ctx)
 		default:
-			return h.changes(ctx, object, entry)
+			return h.changes(ctx, b, position, object, entry)
 		}
 	})
 
+	// dispatched by conversations across entries instead
+	if h.orderedConversations {
+		return g.Wait()
+	}
+
 	g.Go(func() error {
 		select {
 		case <-ctx.Done():
 			return context.Cause(ctx)
 		default:
-			return h.messaging(ctx, object, entry)
+			return h.messaging(ctx, b, position, object, entry)
 		}
 	})
 
@@ -2,13 +2,61 @@ package gometawebhooks
 
 import (
 	"context"
-
-	"golang.org/x/sync/errgroup"
+	"encoding/json"
+	"fmt"
 )
 
 type Event struct {
 	Object Object  `json:"object"`
 	Entry  []Entry `json:"entry"`
+
+	// original payload of unsupported objects, see Options.RawHandler
+	Raw json.RawMessage `json:"-"`
+
+	// outside the replay window, see Options.FlagStaleEvents
+	Stale bool `json:"-"`
+}
+
+// Envelope of a payload, its changes and messaging are decoded once the object is known
+type payload struct {
+	Object *string        `json:"object"`
+	Entry  []payloadEntry `json:"entry"`
+}
+
+// Builds the event of the decoded payload, events of unsupported objects are kept when raw handlers are set
+func (hooks Webhooks) parseEvent(d *decodedPayload) (Event, error) {
+	var event Event
+
+	var raw payload
+	if err := assignTree(d.root, &raw); err != nil {
+		return event, err
+	}
+
+	if raw.Object != nil {
+		if *raw.Object == "" {
+			return event, ErrObjectRequired
+		}
+
+		event.Object = Object(*raw.Object)
+		if !event.Object.supported() {
+			if !hooks.preserveUnknown() {
+				return event, fmt.Errorf("'%s': %w", event.Object, ErrObjectNotSupported)
+			}
+			event.Raw = append(json.RawMessage(nil), d.body...)
+		}
+	}
+
+	if raw.Entry != nil {
+		event.Entry = make([]Entry, len(raw.Entry))
+		for i, entry := range raw.Entry {
+			var err error
+			if event.Entry[i], err = hooks.parseEntry(d, i, event.Object, entry); err != nil {
+				return event, err
+			}
+		}
+	}
+
+	return event, nil
 }
 
 func (h Webhooks) Handle(ctx context.Context, event Event) error {
@@ -16,17 +64,37 @@ func (h Webhooks) Handle(ctx context.Context, event Event) error {
 		return nil
 	}
 
-	g, ctx := errgroup.WithContext(ctx)
-	g.SetLimit(len(event.Entry))
-	for _, entry := range event.Entry {
+	if event.Stale {
+		ctx = context.WithValue(ctx, staleKey{}, true)
+	}
+
+	b := h.newBatch()
+
+	g, ctx := b.group(ctx)
+	if h.orderedConversations {
+		g.SetLimit(len(event.Entry) + 1)
+		g.Go(func() error {
+			return h.conversations(ctx, b, event.Object, event.Entry)
+		})
+	} else {
+		g.SetLimit(len(event.Entry))
+	}
+	for i, entry := range event.Entry {
 		g.Go(func() error {
 			select {
 			case <-ctx.Done():
 				return context.Cause(ctx)
 			default:
-				return h.entry(ctx, event.Object, entry)
+				return h.entry(ctx, b, i, event.Object, entry)
 			}
 		})
 	}
-	return g.Wait()
+
+	err := b.err(g.Wait())
+
+	// re-panicked whichever error stopped dispatching
+	if panicErr := b.finish(); panicErr != nil {
+		panic(panicErr)
+	}
+	return err
 }
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+
+	gometawebhooks "github.com/pnmcosta/go-meta-webhooks"
+)
+
+var (
+	ErrDispatcherClosed = errors.New("dispatcher closed")
+)
+
+// Dispatches parsed events to be handled
+type Dispatcher interface {
+	Dispatch(ctx context.Context, event Event) error
+}
+
+// Handles errors raised by events dispatched in the background, after the HTTP response was sent
+type AsyncErrorHandlerFunc func(ctx context.Context, event Event, err error)
+
+// AsyncOption is a configuration option for the async dispatcher
+type AsyncOption func(*asyncDispatcher) error
+
+// AsyncOptions is a namespace var for async dispatcher configuration options
+var AsyncOptions = AsyncDispatcherOptions{}
+
+// AsyncDispatcherOptions is a namespace for async dispatcher configuration option methods
+type AsyncDispatcherOptions struct{}
+
+// Sets the number of background workers handling events, defaults to GOMAXPROCS
+func (AsyncDispatcherOptions) Workers(n int) AsyncOption {
+	return func(d *asyncDispatcher) error {
+		d.workers = n
+		return nil
+	}
+}
+
+// Sets the number of events queued before Dispatch blocks, defaults to the number of workers
+func (AsyncDispatcherOptions) QueueSize(n int) AsyncOption {
+	return func(d *asyncDispatcher) error {
+		d.queueSize = n
+		return nil
+	}
+}
+
+// Sets the handler for errors raised by events handled in the background
+func (AsyncDispatcherOptions) ErrorHandler(fn AsyncErrorHandlerFunc) AsyncOption {
+	return func(d *asyncDispatcher) error {
+		d.errorHandler = fn
+		return nil
+	}
+}
+
+// Sets the Dispatcher used to handle events after the HTTP response is sent
+func (HTTPHandlerOptions) Dispatcher(d Dispatcher) HTTPOption {
+	return func(h *httpHandler) error {
+		h.dispatcher = d
+		return nil
+	}
+}
+
+var _ Dispatcher = (*asyncDispatcher)(nil)
+
+type asyncDispatcher struct {
+	hooks gometawebhooks.WebhooksHandler
+
+	workers      int
+	queueSize    int
+	errorHandler AsyncErrorHandlerFunc
+
+	queue  chan Event
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	// closed on shutdown to unblock dispatches waiting on a full queue
+	done chan struct{}
+	// closed once queued and in-flight events are handled
+	drained chan struct{}
+	// dispatches which may still send to the queue
+	sending sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// Creates and starts a dispatcher handling events on a bounded pool of background workers
+func NewAsyncDispatcher(hooks gometawebhooks.WebhooksHandler, opts ...AsyncOption) (*asyncDispatcher, error) {
+	d := &asyncDispatcher{
+		hooks: hooks,
+	}
+
+	for _, opt := range opts {
+		if err := opt(d); err != nil {
+			return nil, wrapErr(err, gometawebhooks.ErrApplyingOption)
+		}
+	}
+
+	if d.workers <= 0 {
+		d.workers = runtime.GOMAXPROCS(0)
+	}
+
+	if d.queueSize <= 0 {
+		d.queueSize = d.workers
+	}
+
+	d.queue = make(chan Event, d.queueSize)
+	d.done = make(chan struct{})
+	d.drained = make(chan struct{})
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+
+	d.wg.Add(d.workers)
+	for range d.workers {
+		go d.work()
+	}
+
+	return d, nil
+}
+
+func (d *asyncDispatcher) work() {
+	defer d.wg.Done()
+
+	for event := range d.queue {
+		if err := d.hooks.Handle(d.ctx, event); err != nil && d.errorHandler != nil {
+			d.errorHandler(d.ctx, event, err)
+		}
+	}
+}
+
+// Queues event to be handled in the background, blocks while the queue is full until ctx is done or
+// the dispatcher is shut down
+func (d *asyncDispatcher) Dispatch(ctx context.Context, event Event) error {
+	d.mu.RLock()
+	if d.closed {
+		d.mu.RUnlock()
+		return ErrDispatcherClosed
+	}
+	d.sending.Add(1)
+	d.mu.RUnlock()
+	defer d.sending.Done()
+
+	select {
+	case d.queue <- event:
+		return nil
+	case <-d.done:
+		return ErrDispatcherClosed
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// Stops accepting events and waits for queued and in-flight events to be handled, when ctx is done
+// before draining in-flight events are cancelled and the ctx error is returned.
+func (d *asyncDispatcher) Shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.done)
+		go d.drain()
+	}
+	d.mu.Unlock()
+
+	select {
+	case <-d.drained:
+		d.cancel()
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		return context.Cause(ctx)
+	}
+}
+
+// Closes the queue once no dispatch can send to it, and waits for workers to handle queued events
+func (d *asyncDispatcher) drain() {
+	d.sending.Wait()
+	close(d.queue)
+	d.wg.Wait()
+	close(d.drained)
+}
@@ -0,0 +1,266 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+func TestAsyncDispatch(t *testing.T) {
+	t.Parallel()
+
+	// closed once the response is written, handlers waiting on it prove the request didn't wait for them
+	var responded chan struct{}
+
+	scenarios := []hookScenario{
+		{
+			name:   "invalid payload",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+				}
+			},
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:   "responds before handling",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"mentions","value":{"media_id":"999"}},{"field":"mentions","value":{"media_id":"888"}}]}]}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				responded := responded
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramMentionHandler(testHandler{func(ctx context.Context) error {
+						select {
+						case <-responded:
+							scenario.trigger("mention")
+						case <-time.After(time.Second):
+							scenario.trigger("handled before responding")
+						}
+						return nil
+					}}),
+				}
+			},
+			asyncOptions: func(scenario *hookScenario) []handler.AsyncOption {
+				return []handler.AsyncOption{
+					handler.AsyncOptions.Workers(1),
+				}
+			},
+			expected: http.StatusOK,
+			expectedHandlers: map[string]int{
+				"mention": 2,
+			},
+		},
+		{
+			name:   "reports errors",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"mentions","value":{"media_id":"999"}}]}]}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramMentionHandler(testHandler{func(ctx context.Context) error {
+						return errors.New("failed")
+					}}),
+				}
+			},
+			asyncOptions: func(scenario *hookScenario) []handler.AsyncOption {
+				return []handler.AsyncOption{
+					handler.AsyncOptions.ErrorHandler(func(ctx context.Context, event handler.Event, err error) {
+						scenario.trigger(err.Error())
+					}),
+				}
+			},
+			expected: http.StatusOK,
+			expectedHandlers: map[string]int{
+				"failed": 1,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.test(t, func(t *testing.T) {
+			responded = make(chan struct{})
+			hooks, req := scenario.setup(t)
+
+			var opts []handler.AsyncOption
+			if scenario.asyncOptions != nil {
+				opts = scenario.asyncOptions(&scenario)
+			}
+
+			dispatcher, err := handler.NewAsyncDispatcher(hooks, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			h, err := handler.NewHTTPHandler(hooks, handler.HTTPOptions.Dispatcher(dispatcher))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			close(responded)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			if err := dispatcher.Shutdown(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			scenario.assert(t, rec.Code, scenario.bodyBytes, nil)
+		})
+	}
+}
+
+func TestAsyncShutdown(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	hooks, err := handler.New(
+		handler.Options.InstagramMentionHandler(testHandler{func(ctx context.Context) error {
+			select {
+			case <-release:
+				return nil
+			case <-ctx.Done():
+				return context.Cause(ctx)
+			}
+		}}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var handled error
+	done := make(chan struct{})
+	dispatcher, err := handler.NewAsyncDispatcher(hooks, handler.AsyncOptions.ErrorHandler(func(ctx context.Context, event handler.Event, err error) {
+		handled = err
+		close(done)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := handler.Event{
+		Object: handler.Instagram,
+		Entry: []handler.Entry{{
+			Id:   "123",
+			Time: 1569262486134,
+			Changes: []handler.Change{{
+				Field: "mentions",
+				Value: handler.Mention{MediaID: "999"},
+			}},
+		}},
+	}
+
+	if err := dispatcher.Dispatch(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := dispatcher.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected error %v, but got %v.", context.DeadlineExceeded, err)
+	}
+
+	<-done
+	if !errors.Is(handled, context.Canceled) {
+		t.Errorf("Expected error %v, but got %v.", context.Canceled, handled)
+	}
+
+	if err := dispatcher.Dispatch(context.Background(), event); !errors.Is(err, handler.ErrDispatcherClosed) {
+		t.Errorf("Expected error %v, but got %v.", handler.ErrDispatcherClosed, err)
+	}
+	close(release)
+}
+
+func TestAsyncShutdownFullQueue(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	hooks, err := handler.New(
+		handler.Options.InstagramMentionHandler(testHandler{func(ctx context.Context) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		}}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dispatcher, err := handler.NewAsyncDispatcher(hooks, handler.AsyncOptions.Workers(1), handler.AsyncOptions.QueueSize(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := handler.Event{
+		Object: handler.Instagram,
+		Entry: []handler.Entry{{
+			Id:   "123",
+			Time: 1569262486134,
+			Changes: []handler.Change{{
+				Field: "mentions",
+				Value: handler.Mention{MediaID: "999"},
+			}},
+		}},
+	}
+
+	// the worker handles the first event and the second fills the queue
+	if err := dispatcher.Dispatch(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	if err := dispatcher.Dispatch(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+
+	blocked := make(chan error)
+	go func() {
+		blocked <- dispatcher.Dispatch(context.Background(), event)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := dispatcher.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected error %v, but got %v.", context.DeadlineExceeded, err)
+	}
+
+	if err := <-blocked; !errors.Is(err, handler.ErrDispatcherClosed) {
+		t.Errorf("Expected error %v, but got %v.", handler.ErrDispatcherClosed, err)
+	}
+	close(release)
+}
+
+func TestAsyncRequestParser(t *testing.T) {
+	t.Parallel()
+
+	hooks, err := handler.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dispatcher, err := handler.NewAsyncDispatcher(hooks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dispatcher.Shutdown(context.Background())
+
+	// only implements DefaultHandler
+	wrapped := struct{ handler.DefaultHandler }{hooks}
+
+	if _, err := handler.NewHTTPHandler(wrapped, handler.HTTPOptions.Dispatcher(dispatcher)); !errors.Is(err, handler.ErrRequestParser) {
+		t.Errorf("Expected error %v, but got %v.", handler.ErrRequestParser, err)
+	}
+}
@@ -0,0 +1,65 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+
+	gometawebhooks "github.com/pnmcosta/go-meta-webhooks"
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+const benchPayload = `{"object":"instagram","entry":[` +
+	`{"id":"123","time":1569262486134,"messaging":[` +
+	`{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"1","text":"hello","attachments":[{"type":"image","payload":{"url":"https://example.com/image.png"}}]}},` +
+	`{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"postback":{"mid":"2","title":"Get Started","payload":"START"}},` +
+	`{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"reaction":{"mid":"3","action":"react","reaction":"love","emoji":"❤"}},` +
+	`{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"read":{"mid":"4"}}]},` +
+	`{"id":"456","time":1569262486134,"changes":[` +
+	`{"field":"mentions","value":{"media_id":"999","comment_id":"4444"}},` +
+	`{"field":"comments","value":{"id":"5555","text":"nice","from":{"id":"777","username":"someone"},"media":{"id":"999","media_product_type":"FEED"}}}]}]}`
+
+// Compares validating and parsing separately, which decodes the payload twice, with DecodePayload, which
+// validates and parses a single decoded payload, also with a registered messaging type
+func BenchmarkPayload(b *testing.B) {
+	hooks, err := handler.New()
+	if err != nil {
+		b.Fatal(err)
+	}
+	registered, err := handler.New(handler.Options.MessagingType("message_edit", gometawebhooks.MessagingTypeFunc(func(ctx context.Context, object handler.Object, entry handler.Entry, edit messageEdit) error {
+		return nil
+	})))
+	if err != nil {
+		b.Fatal(err)
+	}
+	body := []byte(benchPayload)
+
+	b.Run("validate+parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			if err := hooks.ValidatePayload(body); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := hooks.ParsePayload(body); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("decode", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			if _, err := hooks.DecodePayload(body); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("decode registered", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			if _, err := registered.DecodePayload(body); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,215 @@
+package handler_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	gometawebhooks "github.com/pnmcosta/go-meta-webhooks"
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+func TestRequestBody(t *testing.T) {
+	t.Parallel()
+
+	const payload = `{"object":"instagram", "entry":[]}`
+
+	scenarios := []struct {
+		name      string
+		options   []handler.Option
+		encoding  string
+		body      []byte
+		expectErr error
+		status    int
+	}{
+		{
+			name: "within limit",
+			options: []handler.Option{
+				handler.Options.MaxBodyBytes(int64(len(payload))),
+			},
+			body:   []byte(payload),
+			status: http.StatusOK,
+		},
+		{
+			name: "too large",
+			options: []handler.Option{
+				handler.Options.MaxBodyBytes(int64(len(payload) - 1)),
+			},
+			body:      []byte(payload),
+			expectErr: gometawebhooks.ErrPayloadTooLarge,
+			status:    http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:     "gzip",
+			encoding: "gzip",
+			body:     gzipBytes(t, []byte(payload)),
+			status:   http.StatusOK,
+		},
+		{
+			name: "gzip decompressed too large",
+			options: []handler.Option{
+				handler.Options.MaxBodyBytes(1024),
+			},
+			encoding:  "gzip",
+			body:      gzipBytes(t, bytes.Repeat([]byte(" "), 1024*1024)),
+			expectErr: gometawebhooks.ErrPayloadTooLarge,
+			status:    http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:      "invalid gzip",
+			encoding:  "gzip",
+			body:      []byte(payload),
+			expectErr: gometawebhooks.ErrDecompressingPayload,
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "unsupported encoding",
+			encoding:  "br",
+			body:      []byte(payload),
+			expectErr: gometawebhooks.ErrUnsupportedContentEncoding,
+			status:    http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			hooks, err := handler.New(append([]handler.Option{
+				handler.Options.CompileSchema(),
+				handler.Options.Secret("very_secret"),
+			}, scenario.options...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/meta", bytes.NewReader(scenario.body))
+			// signed over the uncompressed payload
+			req.Header.Set("X-Hub-Signature-256", genHmac("very_secret", payload))
+			if scenario.encoding != "" {
+				req.Header.Set("Content-Encoding", scenario.encoding)
+			}
+
+			event, result, err := hooks.HandleRequest(context.Background(), req)
+			if status := handler.StatusCode(err); status != scenario.status {
+				t.Errorf("Expected status %d, but got %d", scenario.status, status)
+			}
+
+			if scenario.expectErr != nil {
+				if !errors.Is(err, scenario.expectErr) {
+					t.Errorf("Expected error %v, but got %v", scenario.expectErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+
+			if string(result) != payload {
+				t.Errorf("Expected body %s, but got %s", payload, result)
+			}
+
+			expected := handler.Event{Object: handler.Instagram, Entry: []handler.Entry{}}
+			if !reflect.DeepEqual(event, expected) {
+				t.Errorf("Expected %v, but got %v", expected, event)
+			}
+		})
+	}
+}
+
+func gzipBytes(t *testing.T, b []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRouterBodyLimit(t *testing.T) {
+	t.Parallel()
+
+	hooks, err := handler.New(handler.Options.CompileSchema())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rt, err := handler.NewRouter(
+		handler.RouterOptions.MaxBodyBytes(8),
+		handler.RouterOptions.Route("a", hooks),
+		handler.RouterOptions.EntryIds("a", "123"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/meta", strings.NewReader(`{"object":"instagram", "entry":[]}`))
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status %d, but got %d", http.StatusRequestEntityTooLarge, rec.Code)
+	}
+}
+
+// Counts the bytes read from a reader
+type countingReader struct {
+	io.Reader
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.n += n
+	return n, err
+}
+
+func TestRouterDefaultBodyLimit(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name     string
+		limits   []int64
+		expected int
+	}{
+		{"largest app limit", []int64{8, 16}, 1024},
+		{"unbounded app", []int64{8, 0}, 1 << 20},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			var opts []handler.RouterOption
+			for i, limit := range scenario.limits {
+				hooks, err := handler.New(handler.Options.MaxBodyBytes(limit))
+				if err != nil {
+					t.Fatal(err)
+				}
+				opts = append(opts, handler.RouterOptions.Route(strconv.Itoa(i), hooks))
+			}
+
+			rt, err := handler.NewRouter(append(opts, handler.RouterOptions.EntryIds("0", "123"))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			body := &countingReader{Reader: strings.NewReader(strings.Repeat(" ", 1<<20))}
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/meta", body)
+			rec := httptest.NewRecorder()
+			rt.ServeHTTP(rec, req)
+
+			if body.n > scenario.expected {
+				t.Errorf("Expected at most %d bytes read, but got %d", scenario.expected, body.n)
+			}
+		})
+	}
+}
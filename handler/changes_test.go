@@ -37,6 +37,40 @@ func TestHandleChange(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:   "instagram messages field",
+			method: http.MethodPost,
+			body: strings.NewReader(`{
+				"object":"instagram",
+				"entry":[{
+					"id":"123",
+					"time":1569262486134,
+					"changes":[{
+							"field": "messages",
+							"value": {
+								"sender": {"id": "567"},
+								"recipient": {"id": "123"},
+								"timestamp": 1569262485349,
+								"message": {"mid": "890"}
+							}
+					}]
+				}]
+			}`),
+			expectErr: gometawebhooks.ErrChangesFieldNotImplemented,
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("instagram")
+						return nil
+					}}),
+					handler.Options.WhatsAppHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("whatsapp")
+						return nil
+					}}),
+				}
+			},
+		},
 		{
 			name:   "handles many",
 			method: http.MethodPost,
@@ -241,6 +275,181 @@ func TestHandleChange(t *testing.T) {
 				"storyInsights": 1,
 			},
 		},
+		{
+			name:   "comment",
+			method: http.MethodPost,
+			body: strings.NewReader(`{
+				"object":"instagram", 
+				"entry":[{
+					"id":"123",
+					"time":1569262486134,
+					"changes":[{ 
+							"field": "comments",
+							"value": {
+								"id": "17865799348089039",
+								"text": "This is an example.",
+								"from": {
+									"id": "232323232",
+									"username": "test"
+								},
+								"media": {
+									"id": "123123123",
+									"media_product_type": "FEED"
+								},
+								"parent_id": "1231231234"
+							}
+					}]
+				}]
+			}`),
+			expected: handler.Event{
+				Object: handler.Instagram,
+				Entry: []handler.Entry{{
+					Id:   "123",
+					Time: 1569262486134,
+					Changes: []handler.Change{{
+						Field: "comments",
+						Value: handler.Comment{
+							Id:   "17865799348089039",
+							Text: "This is an example.",
+							From: handler.CommentFrom{
+								Id:       "232323232",
+								Username: "test",
+							},
+							Media: handler.CommentMedia{
+								Id:               "123123123",
+								MediaProductType: "FEED",
+							},
+							ParentId: "1231231234",
+						},
+					}},
+				}},
+			},
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramCommentHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("comment")
+						return nil
+					}}),
+				}
+			},
+			expectedHandlers: map[string]int{
+				"comment": 1,
+			},
+		},
+		{
+			name:   "live comment",
+			method: http.MethodPost,
+			body: strings.NewReader(`{
+				"object":"instagram", 
+				"entry":[{
+					"id":"123",
+					"time":1569262486134,
+					"changes":[{ 
+							"field": "live_comments",
+							"value": {
+								"id": "17865799348089039",
+								"text": "This is an example.",
+								"from": {
+									"id": "232323232",
+									"username": "test"
+								},
+								"media": {
+									"id": "123123123",
+									"media_product_type": "LIVE"
+								}
+							}
+					}]
+				}]
+			}`),
+			expected: handler.Event{
+				Object: handler.Instagram,
+				Entry: []handler.Entry{{
+					Id:   "123",
+					Time: 1569262486134,
+					Changes: []handler.Change{{
+						Field: "live_comments",
+						Value: handler.LiveComment{
+							Id:   "17865799348089039",
+							Text: "This is an example.",
+							From: handler.CommentFrom{
+								Id:       "232323232",
+								Username: "test",
+							},
+							Media: handler.CommentMedia{
+								Id:               "123123123",
+								MediaProductType: "LIVE",
+							},
+						},
+					}},
+				}},
+			},
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramLiveCommentHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("liveComment")
+						return nil
+					}}),
+				}
+			},
+			expectedHandlers: map[string]int{
+				"liveComment": 1,
+			},
+		},
+		{
+			name:   "live comment handler not defined",
+			method: http.MethodPost,
+			body: strings.NewReader(`{
+				"object":"instagram", 
+				"entry":[{
+					"id":"123",
+					"time":1569262486134,
+					"changes":[{ 
+							"field": "live_comments",
+							"value": {
+								"id": "17865799348089039",
+								"text": "This is an example."
+							}
+					}]
+				}]
+			}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramCommentHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("comment")
+						return nil
+					}}),
+				}
+			},
+			expectErr: gometawebhooks.ErrInstagramLiveCommentHandlerNotDefined,
+		},
+		{
+			name:   "changes handler without comments",
+			method: http.MethodPost,
+			body: strings.NewReader(`{
+				"object":"instagram", 
+				"entry":[{
+					"id":"123",
+					"time":1569262486134,
+					"changes":[{ 
+							"field": "comments",
+							"value": {
+								"id": "17865799348089039",
+								"text": "This is an example."
+							}
+					}]
+				}]
+			}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramChangesHandler(mentionsHandler{}),
+				}
+			},
+			expectErr: gometawebhooks.ErrInstagramCommentHandlerNotDefined,
+		},
 		{
 			name:   "deadline exceeded",
 			method: http.MethodPost,
@@ -309,3 +518,14 @@ func TestHandleChange(t *testing.T) {
 		})
 	}
 }
+
+// Implements handler.InstagramChangesHandler without the optional comment handlers
+type mentionsHandler struct{}
+
+func (mentionsHandler) InstagramMention(ctx context.Context, object handler.Object, entry handler.Entry, mention handler.Mention) error {
+	return nil
+}
+
+func (mentionsHandler) InstagramStoryInsights(ctx context.Context, object handler.Object, entry handler.Entry, storyInsights handler.StoryInsights) error {
+	return nil
+}
@@ -0,0 +1,141 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+// Tracks the maximum number of concurrent handler invocations
+type concurrencyHandler struct {
+	running *atomic.Int32
+	max     *atomic.Int32
+}
+
+func newConcurrencyHandler() concurrencyHandler {
+	return concurrencyHandler{&atomic.Int32{}, &atomic.Int32{}}
+}
+
+func (h concurrencyHandler) run(ctx context.Context) error {
+	return h.runFor(ctx, time.Millisecond)
+}
+
+// Runs for d ignoring ctx
+func (h concurrencyHandler) runFor(ctx context.Context, d time.Duration) error {
+	running := h.running.Add(1)
+	defer h.running.Add(-1)
+
+	for {
+		max := h.max.Load()
+		if running <= max || h.max.CompareAndSwap(max, running) {
+			break
+		}
+	}
+
+	time.Sleep(d)
+	return nil
+}
+
+func TestMaxConcurrency(t *testing.T) {
+	t.Parallel()
+
+	body := `{"object":"instagram","entry":[{"id":"789","time":1569262486134,"changes":[{"field":"mentions","value":{"media_id":"999"}}]},{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"1"}},{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"2"}}]},{"id":"456","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"456"},"timestamp":1569262485349,"message":{"mid":"3"}}]}]}`
+
+	scenarios := []struct {
+		hookScenario
+		limit   handler.Option
+		calls   int
+		maximum int32
+	}{
+		{
+			hookScenario: hookScenario{name: "per call"},
+			limit:        handler.Options.MaxConcurrency(2),
+			calls:        1,
+			maximum:      2,
+		},
+		{
+			hookScenario: hookScenario{name: "across calls"},
+			limit:        handler.Options.GlobalMaxConcurrency(1),
+			calls:        3,
+			maximum:      1,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.test(t, func(t *testing.T) {
+			h := newConcurrencyHandler()
+			hooks, err := handler.New(
+				handler.Options.CompileSchema(),
+				scenario.limit,
+				handler.Options.InstagramMentionHandler(testHandler{h.run}),
+				handler.Options.InstagramMessageHandler(testHandler{h.run}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var wg sync.WaitGroup
+			for range scenario.calls {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					req := newRequest(http.MethodPost, body)
+					if _, _, err := hooks.HandleRequest(context.Background(), req); err != nil {
+						t.Errorf("Expected no error, but got: %v", err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if max := h.max.Load(); max > scenario.maximum {
+				t.Errorf("Expected at most %d concurrent handlers, but got %d", scenario.maximum, max)
+			}
+		})
+	}
+}
+
+func TestMaxConcurrencyTimeout(t *testing.T) {
+	t.Parallel()
+
+	body := `{"object":"instagram","entry":[{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"1"}},{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"2"}}]}]}`
+
+	h := newConcurrencyHandler()
+	finished := make(chan struct{}, 2)
+	hooks, err := handler.New(
+		handler.Options.MaxConcurrency(1),
+		handler.Options.ContinueOnError(true),
+		handler.Options.HandlerTimeout(time.Millisecond),
+		// ignores ctx, outliving its timeout
+		handler.Options.InstagramMessageHandler(testHandler{func(ctx context.Context) error {
+			defer func() { finished <- struct{}{} }()
+			return h.runFor(ctx, 20*time.Millisecond)
+		}}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := newRequest(http.MethodPost, body)
+	if _, _, err := hooks.HandleRequest(context.Background(), req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected error %v, but got %v", context.DeadlineExceeded, err)
+	}
+	<-finished
+	<-finished
+
+	if max := h.max.Load(); max > 1 {
+		t.Errorf("Expected at most 1 concurrent handler, but got %d", max)
+	}
+}
+
+func newRequest(method string, body string) *http.Request {
+	req, _ := http.NewRequest(method, "/webhooks/meta", strings.NewReader(body))
+	return req
+}
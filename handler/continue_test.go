@@ -0,0 +1,153 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+var errMessage = errors.New("message failed")
+
+type failingMessageHandler struct {
+	scenario *hookScenario
+	fail     map[string]bool
+}
+
+// InstagramMessage implements handler.InstagramMessageHandler.
+func (h failingMessageHandler) InstagramMessage(ctx context.Context, object handler.Object, entry handler.Entry, message handler.MessagingMessage) error {
+	h.scenario.trigger("message")
+	if h.fail[message.Message.Id] {
+		return errMessage
+	}
+	return nil
+}
+
+func TestContinueOnError(t *testing.T) {
+	t.Parallel()
+
+	body := `{"object":"instagram","entry":[{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"1"}},{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"2"}}]},{"id":"456","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"456"},"timestamp":1569262485349,"message":{"mid":"3"}}]}]}`
+
+	scenarios := []hookScenario{
+		{
+			name:   "aggregates every failed item",
+			method: http.MethodPost,
+			body:   strings.NewReader(body),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.ContinueOnError(true),
+					handler.Options.InstagramMessageHandler(failingMessageHandler{scenario, map[string]bool{"1": true, "3": true}}),
+				}
+			},
+			expected: []handler.ItemError{
+				{EntryId: "123", Index: 0, Kind: "message", Err: errMessage},
+				{EntryId: "456", Index: 0, Kind: "message", Err: errMessage},
+			},
+			expectedHandlers: map[string]int{"message": 3},
+		},
+		{
+			name:   "no failed items",
+			method: http.MethodPost,
+			body:   strings.NewReader(body),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.ContinueOnError(true),
+					handler.Options.InstagramMessageHandler(failingMessageHandler{scenario, nil}),
+				}
+			},
+			expected:         []handler.ItemError(nil),
+			expectedHandlers: map[string]int{"message": 3},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.test(t, func(t *testing.T) {
+			hooks, req := scenario.setup(t)
+
+			ctx, cancel := context.WithTimeout(context.Background(), scenario.timeout)
+			defer cancel()
+
+			_, payload, err := hooks.HandleRequest(ctx, req)
+
+			var result []handler.ItemError
+			if err != nil {
+				var dispatchErr *handler.DispatchError
+				if !errors.As(err, &dispatchErr) {
+					t.Fatalf("Expected *DispatchError, but got %v", err)
+				}
+
+				if !errors.Is(err, errMessage) {
+					t.Errorf("Expected error %v, but got %v", errMessage, err)
+				}
+
+				for _, itemErr := range dispatchErr.Errors {
+					result = append(result, handler.ItemError{
+						EntryId: itemErr.EntryId,
+						Index:   itemErr.Index,
+						Kind:    itemErr.Kind,
+						Err:     itemErr.Err,
+					})
+				}
+				err = nil
+			}
+
+			scenario.assert(t, result, payload, err)
+		})
+	}
+}
+
+// Fails the first message and blocks the others until ctx is done
+type blockingMessageHandler struct{}
+
+// InstagramMessage implements handler.InstagramMessageHandler.
+func (blockingMessageHandler) InstagramMessage(ctx context.Context, object handler.Object, entry handler.Entry, message handler.MessagingMessage) error {
+	if message.Message.Id == "1" {
+		return errMessage
+	}
+	<-ctx.Done()
+	return context.Cause(ctx)
+}
+
+// the second message blocks until ctx is done and the third is never dispatched
+func TestContinueOnErrorCancelled(t *testing.T) {
+	t.Parallel()
+
+	hooks, err := handler.New(
+		handler.Options.ContinueOnError(true),
+		handler.Options.MaxConcurrency(1),
+		handler.Options.InstagramMessageHandler(blockingMessageHandler{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event, err := hooks.ParsePayload([]byte(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"1"}},{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"2"}},{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"3"}}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err = hooks.Handle(ctx, event)
+
+	var dispatchErr *handler.DispatchError
+	if !errors.As(err, &dispatchErr) {
+		t.Fatalf("Expected *DispatchError, but got %v", err)
+	}
+	if !errors.Is(err, errMessage) {
+		t.Errorf("Expected error %v, but got %v", errMessage, err)
+	}
+	if !errors.Is(dispatchErr.Err, context.DeadlineExceeded) {
+		t.Errorf("Expected error %v, but got %v", context.DeadlineExceeded, dispatchErr.Err)
+	}
+	if len(dispatchErr.Errors) != 2 {
+		t.Errorf("Expected 2 item errors, but got %v", dispatchErr.Errors)
+	}
+}
@@ -0,0 +1,140 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	gometawebhooks "github.com/pnmcosta/go-meta-webhooks"
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+// Parsing builds the event from the decoded payload tree, it must match decoding the payload with encoding/json
+func TestDecodeParity(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name      string
+		payload   string
+		expectErr bool
+	}{
+		{
+			name: "instagram messaging",
+			payload: `{"object":"instagram","entry":[{"id":"123","time":1569262486134,"messaging":[
+				{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"1","text":"hello","is_echo":true,
+					"attachments":[{"type":"image","payload":{"url":"https://example.com/image.png","title":"image"}},{"type":"ig_reel","payload":{"url":"https://example.com","reel_video_id":"42"}}],
+					"reply_to":{"story":{"id":"7","url":"https://example.com/story"}},"quick_reply":{"payload":"QUICK"},
+					"referral":{"type":"OPEN_THREAD","source":"ADS","ref":"REF","product":{"id":"9"}}}},
+				{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"postback":{"mid":"2","title":"Get Started","payload":"START","referral":{"type":"OPEN_THREAD","source":"SHORTLINK"}}},
+				{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"referral":{"type":"OPEN_THREAD","source":"ADS","ref":"REF"}},
+				{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"reaction":{"mid":"3","action":"react","reaction":"love","emoji":"❤"}},
+				{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"read":{"mid":"4","watermark":1569262485000}}]}]}`,
+		},
+		{
+			name: "instagram changes",
+			payload: `{"object":"instagram","entry":[{"id":"456","time":1569262486,"changes":[
+				{"field":"mentions","value":{"media_id":"999","comment_id":"4444"}},
+				{"field":"story_insights","value":{"media_id":"999","exits":1,"replies":2,"reach":3,"taps_forward":4,"taps_back":5,"impressions":6}},
+				{"field":"comments","value":{"id":"5555","text":"nice","from":{"id":"777","username":"someone"},"media":{"id":"999","media_product_type":"FEED"},"parent_id":"1"}},
+				{"field":"live_comments","value":{"id":"6666","text":"live","from":{"id":"777","username":"someone"},"media":{"id":"999","media_product_type":"LIVE"}}}]}]}`,
+		},
+		{
+			name:    "case-insensitive keys",
+			payload: `{"object":"page","entry":[{"id":"123","time":1569262486134,"messaging":[{"Sender":{"ID":"567"},"recipient":{"id":"123"},"Timestamp":1569262485349,"message":{"MID":"1","Text":"hello","unknown":{"nested":[1,2]}}}]}]}`,
+		},
+		{
+			name: "whatsapp",
+			payload: `{"object":"whatsapp_business_account","entry":[{"id":"WABA_ID","changes":[{"field":"messages","value":{"messaging_product":"whatsapp",
+				"metadata":{"display_phone_number":"16505551111","phone_number_id":"123456123"},
+				"contacts":[{"profile":{"name":"Kerry Fisher"},"wa_id":"16315551234"}],
+				"messages":[
+					{"from":"16315551234","id":"wamid.1","timestamp":"1669233778","type":"text","text":{"body":"hello"},"context":{"from":"16505551111","id":"wamid.0"}},
+					{"from":"16315551234","id":"wamid.2","timestamp":"1669233778","type":"image","image":{"id":"1","mime_type":"image/jpeg","sha256":"abc","caption":"look"}},
+					{"from":"16315551234","id":"wamid.3","timestamp":"1669233778","type":"audio","audio":{"id":"2","mime_type":"audio/ogg","voice":true}},
+					{"from":"16315551234","id":"wamid.4","timestamp":"1669233778","type":"location","location":{"latitude":38.72,"longitude":-9.14,"name":"Lisbon","address":"Portugal","url":"https://example.com"}},
+					{"from":"16315551234","id":"wamid.5","timestamp":"1669233778","type":"interactive","interactive":{"type":"list_reply","list_reply":{"id":"1","title":"One","description":"First"}}},
+					{"from":"16315551234","id":"wamid.6","timestamp":"1669233778","type":"reaction","reaction":{"message_id":"wamid.1","emoji":"❤"}},
+					{"from":"16315551234","id":"wamid.7","timestamp":"1669233778","type":"unsupported","errors":[{"code":131051,"title":"Message type unknown","error_data":{"details":"unsupported"}}]}],
+				"statuses":[{"id":"wamid.8","status":"sent","timestamp":"1669233778","recipient_id":"16315551234",
+					"conversation":{"id":"CONVERSATION_ID","expiration_timestamp":"1669320178","origin":{"type":"service"}},
+					"pricing":{"billable":true,"pricing_model":"CBP","category":"service"}}]}}]}]}`,
+		},
+		{
+			name:      "type mismatch",
+			payload:   `{"object":"instagram","entry":[{"id":"456","time":1569262486,"changes":[{"field":"story_insights","value":{"media_id":"999","exits":"1"}}]}]}`,
+			expectErr: true,
+		},
+		{
+			name:      "overflow",
+			payload:   `{"object":"instagram","entry":[{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1e400,"message":{"mid":"1"}}]}]}`,
+			expectErr: true,
+		},
+	}
+
+	hooks, err := handler.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			var expected handler.Event
+			expectedErr := json.Unmarshal([]byte(scenario.payload), &expected)
+
+			result, err := hooks.ParsePayload([]byte(scenario.payload))
+			if scenario.expectErr {
+				var typeErr *json.UnmarshalTypeError
+				if !errors.As(expectedErr, &typeErr) || !errors.As(err, &typeErr) || !errors.Is(err, gometawebhooks.ErrParsingPayload) {
+					t.Errorf("Expected type errors, but got %v and %v", expectedErr, err)
+				}
+				return
+			}
+
+			if expectedErr != nil || err != nil {
+				t.Fatalf("Expected no errors, but got %v and %v", expectedErr, err)
+			}
+
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("Expected %+v, but got %+v", expected, result)
+			}
+		})
+	}
+}
+
+// Registered and raw handlers receive the undecoded JSON of their items as sent
+func TestDecodeUndecoded(t *testing.T) {
+	t.Parallel()
+
+	const value = `{ "b": 2, "a": [1.50, "x"] }`
+	payload := `{"object":"instagram","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"custom","value":` + value + `}]}]}`
+
+	hooks, err := handler.New(handler.Options.RawChangeHandler(rawHandler{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event, err := hooks.DecodePayload([]byte(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if raw := string(event.Entry[0].Changes[0].Raw); raw != value {
+		t.Errorf("Expected raw %s, but got %s", value, raw)
+	}
+}
+
+// Payloads are validated against the schema before being parsed
+func TestDecodeInvalid(t *testing.T) {
+	t.Parallel()
+
+	hooks, err := handler.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = hooks.DecodePayload([]byte(`{"object":"instagram","entry":[{"id":"123"}]}`))
+	if !errors.Is(err, gometawebhooks.ErrInvalidPayload) {
+		t.Errorf("Expected error %v, but got %v", gometawebhooks.ErrInvalidPayload, err)
+	}
+}
@@ -0,0 +1,250 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gometawebhooks "github.com/pnmcosta/go-meta-webhooks"
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+func TestDeduplicate(t *testing.T) {
+	t.Parallel()
+	scenarios := []hookScenario{
+		{
+			name:   "messages by mid",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"890","text":"first"}},{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"890","text":"first"}},{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"891","text":"second"}}]}]}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.Deduplicate(gometawebhooks.NewMemoryDedupStore(time.Minute, 10)),
+					handler.Options.InstagramMessageHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("message")
+						return nil
+					}}),
+				}
+			},
+			expectedHandlers: map[string]int{
+				"message": 2,
+			},
+		},
+		{
+			name:   "changes by hash",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"mentions","value":{"media_id":"999"}},{"field":"mentions","value":{"media_id":"999"}}]},{"id":"123","time":1569262486135,"changes":[{"field":"mentions","value":{"media_id":"999"}}]}]}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.Deduplicate(gometawebhooks.NewMemoryDedupStore(time.Minute, 10)),
+					handler.Options.InstagramMentionHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("mention")
+						return nil
+					}}),
+				}
+			},
+			expectedHandlers: map[string]int{
+				"mention": 2,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.test(t, func(t *testing.T) {
+			hooks, req := scenario.setup(t)
+
+			ctx, cancel := context.WithTimeout(context.Background(), scenario.timeout)
+			defer cancel()
+
+			_, payload, err := hooks.HandleRequest(ctx, req)
+
+			scenario.assert(t, nil, payload, err)
+		})
+	}
+}
+
+func TestDeduplicateRedelivery(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	hooks, err := handler.New(
+		handler.Options.Deduplicate(gometawebhooks.NewMemoryDedupStore(time.Minute, 10)),
+		handler.Options.InstagramPostbackHandler(testHandler{func(ctx context.Context) error {
+			calls++
+			if calls == 1 {
+				return errors.New("failed")
+			}
+			return nil
+		}}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event, err := hooks.ParsePayload([]byte(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"postback":{"mid":"890","title":"TITLE","payload":"PAYLOAD"}}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, expectErr := range []bool{true, false, false} {
+		if err := hooks.Handle(context.Background(), event); (err != nil) != expectErr {
+			t.Errorf("Delivery %d expected error %v, but got %v", i, expectErr, err)
+		}
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, but got %d", calls)
+	}
+}
+
+func TestDeduplicateInFlight(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls atomic.Int32
+	hooks, err := handler.New(
+		handler.Options.Deduplicate(gometawebhooks.NewMemoryDedupStore(time.Minute, 10)),
+		handler.Options.InstagramPostbackHandler(testHandler{func(ctx context.Context) error {
+			if calls.Add(1) == 1 {
+				close(started)
+				<-release
+				return errors.New("failed")
+			}
+			return nil
+		}}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event, err := hooks.ParsePayload([]byte(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"postback":{"mid":"890","title":"TITLE","payload":"PAYLOAD"}}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := make(chan error)
+	go func() {
+		first <- hooks.Handle(context.Background(), event)
+	}()
+	<-started
+
+	// the redelivery waits for the first delivery, which fails, and handles the event again
+	redelivery := make(chan error)
+	go func() {
+		redelivery <- hooks.Handle(context.Background(), event)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	if err := <-first; err == nil {
+		t.Errorf("Expected first delivery error")
+	}
+	if err := <-redelivery; err != nil {
+		t.Errorf("Expected no redelivery error, but got %v", err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("Expected 2 calls, but got %d", n)
+	}
+}
+
+func TestMemoryDedupStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := gometawebhooks.NewMemoryDedupStore(20*time.Millisecond, 2)
+	for _, step := range []struct {
+		key      string
+		expected bool
+	}{
+		{"a", false},
+		{"a", true},
+		{"b", false},
+		{"c", false},
+		// evicted as least recently seen over capacity
+		{"a", false},
+		{"c", true},
+	} {
+		if seen, _ := store.Seen(ctx, step.key); seen != step.expected {
+			t.Errorf("Expected %s seen %v, but got %v", step.key, step.expected, seen)
+		}
+		_ = store.Commit(ctx, step.key)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if seen, _ := store.Seen(ctx, "c"); seen {
+		t.Errorf("Expected c expired")
+	}
+
+	_ = store.Forget(ctx, "c")
+	if seen, _ := store.Seen(ctx, "c"); seen {
+		t.Errorf("Expected c forgotten")
+	}
+}
+
+func TestMemoryDedupStorePending(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := gometawebhooks.NewMemoryDedupStore(time.Minute, 0)
+	if seen, _ := store.Seen(ctx, "a"); seen {
+		t.Errorf("Expected a not seen")
+	}
+
+	// a is pending until committed, forgotten or expired
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := store.Seen(timeout, "a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected error %v, but got %v", context.DeadlineExceeded, err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = store.Forget(ctx, "a")
+	}()
+	if seen, _ := store.Seen(ctx, "a"); seen {
+		t.Errorf("Expected a forgotten while pending")
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = store.Commit(ctx, "a")
+	}()
+	if seen, _ := store.Seen(ctx, "a"); !seen {
+		t.Errorf("Expected a committed while pending")
+	}
+
+	expiring := gometawebhooks.NewMemoryDedupStore(10*time.Millisecond, 0)
+	_, _ = expiring.Seen(ctx, "a")
+	if seen, _ := expiring.Seen(ctx, "a"); seen {
+		t.Errorf("Expected a expired while pending")
+	}
+}
+
+func TestMemoryDedupStorePendingCapacity(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := gometawebhooks.NewMemoryDedupStore(time.Minute, 1)
+	_, _ = store.Seen(ctx, "a")
+	_, _ = store.Seen(ctx, "b")
+
+	// a is kept over capacity while pending
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := store.Seen(timeout, "a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected error %v, but got %v", context.DeadlineExceeded, err)
+	}
+
+	// and evicted once committed
+	_ = store.Commit(ctx, "a")
+	_, _ = store.Seen(ctx, "c")
+	if seen, _ := store.Seen(ctx, "a"); seen {
+		t.Errorf("Expected a evicted once committed")
+	}
+}
@@ -13,6 +13,7 @@ import (
 var (
 	ErrReadBodyPayload   = errors.New("error reading body payload")
 	ErrInvalidHTTPMethod = errors.New("invalid HTTP Method")
+	ErrRequestParser     = errors.New("handler does not implement RequestParser")
 )
 
 type DefaultHandler interface {
@@ -22,18 +23,23 @@ type DefaultHandler interface {
 	HandleVerify(r *http.Request) (string, error)
 }
 
-var _ DefaultHandler = (*defaultHandler)(nil)
+// Parses requests without handling them, required by the HTTP handler to dispatch events, see HTTPOptions.Dispatcher
+type RequestParser interface {
+	ParseRequest(r *http.Request) (Event, []byte, error)
+}
+
+var (
+	_ DefaultHandler = (*defaultHandler)(nil)
+	_ RequestParser  = (*defaultHandler)(nil)
+)
 
 type defaultHandler struct {
 	*gometawebhooks.Webhooks
 }
 
+// Creates a handler validating payloads against the embedded schema, unless overridden by Options.Schema or Options.SchemaFS
 func New(opts ...Option) (*defaultHandler, error) {
-	if len(opts) == 0 {
-		opts = append(opts, Options.CompileSchema())
-	}
-
-	hooks, err := gometawebhooks.New(opts...)
+	hooks, err := gometawebhooks.New(append([]Option{Options.CompileSchema()}, opts...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -41,8 +47,22 @@ func New(opts ...Option) (*defaultHandler, error) {
 	return &defaultHandler{hooks}, nil
 }
 
-// Handles Meta Webhooks POST requests, verifies signature if secret is supplied, validates and parses Event payload.
+// Handles Meta Webhooks POST requests, verifies signature if secret is supplied, validates, parses and handles Event payload.
 func (hooks defaultHandler) HandleRequest(ctx context.Context, r *http.Request) (Event, []byte, error) {
+	event, payload, err := hooks.ParseRequest(r)
+	if err != nil {
+		return event, payload, err
+	}
+
+	if err = hooks.Handle(ctx, event); err != nil {
+		// accepts Meta's redelivery of the failed event
+		_ = hooks.ForgetSignature(context.WithoutCancel(ctx), r.Header)
+	}
+	return event, payload, err
+}
+
+// Parses Meta Webhooks POST requests, verifies signature if secret is supplied, validates and parses Event payload without handling it.
+func (hooks defaultHandler) ParseRequest(r *http.Request) (Event, []byte, error) {
 	defer func() {
 		_, _ = io.Copy(io.Discard, r.Body)
 		_ = r.Body.Close()
@@ -54,34 +74,24 @@ func (hooks defaultHandler) HandleRequest(ctx context.Context, r *http.Request)
 		return event, []byte{}, ErrInvalidHTTPMethod
 	}
 
-	payload, err := io.ReadAll(r.Body)
-	if err != nil || len(payload) == 0 {
+	payload, err := hooks.ReadPayload(r.Body, r.Header.Get("Content-Encoding"))
+	if err != nil {
 		return event, payload, wrapErr(err, ErrReadBodyPayload)
 	}
-
-	// normalize header keys
-	headers := make(map[string]string)
-	for k, v := range r.Header {
-		if len(v) > 0 {
-			headers[k] = v[0]
-		}
-	}
-
-	if err := hooks.VerifyPayload(payload, headers); err != nil {
-		return event, payload, err
+	if len(payload) == 0 {
+		return event, payload, ErrReadBodyPayload
 	}
 
-	if err := hooks.ValidatePayload(payload); err != nil {
+	if err := hooks.VerifyPayloadHeader(r.Context(), payload, r.Header); err != nil {
 		return event, payload, err
 	}
 
-	event, err = hooks.ParsePayload(payload)
-	if err != nil {
+	if event, err = hooks.DecodePayloadContext(r.Context(), payload); err != nil {
 		return event, payload, err
 	}
 
-	err = hooks.Handle(ctx, event)
-	return event, payload, err
+	// recorded once verified and parsed, see Options.ReplayCache
+	return event, payload, hooks.RecordSignature(r.Context(), r.Header)
 }
 
 // Verify Meta Webhooks GET requests, when subscribing on App dashboard to objects and fields.
@@ -91,7 +101,7 @@ func (hooks defaultHandler) HandleVerify(r *http.Request) (string, error) {
 	}
 
 	q := r.URL.Query()
-	return hooks.VerifyToken(map[string]string{
+	return hooks.VerifyTokenContext(r.Context(), map[string]string{
 		"hub.mode":         q.Get("hub.mode"),
 		"hub.verify_token": q.Get("hub.verify_token"),
 		"hub.challenge":    q.Get("hub.challenge"),
@@ -99,5 +109,5 @@ func (hooks defaultHandler) HandleVerify(r *http.Request) (string, error) {
 }
 
 func wrapErr(err, target error) error {
-	return fmt.Errorf("%s: %w", err, target)
+	return fmt.Errorf("%w: %w", err, target)
 }
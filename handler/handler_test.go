@@ -34,6 +34,16 @@ func (h testHandler) InstagramStoryInsights(ctx context.Context, object handler.
 	return h.run(ctx)
 }
 
+// InstagramComment implements handler.InstagramCommentHandler.
+func (h testHandler) InstagramComment(ctx context.Context, object handler.Object, entry handler.Entry, comment handler.Comment) error {
+	return h.run(ctx)
+}
+
+// InstagramLiveComment implements handler.InstagramLiveCommentHandler.
+func (h testHandler) InstagramLiveComment(ctx context.Context, object handler.Object, entry handler.Entry, liveComment handler.LiveComment) error {
+	return h.run(ctx)
+}
+
 // InstagramMessage implements handler.InstagramMessageHandler.
 func (h testHandler) InstagramMessage(ctx context.Context, object handler.Object, entry handler.Entry, message handler.MessagingMessage) error {
 	return h.run(ctx)
@@ -49,7 +59,54 @@ func (h testHandler) InstagramReferral(ctx context.Context, object handler.Objec
 	return h.run(ctx)
 }
 
+// InstagramReaction implements handler.InstagramReactionHandler.
+func (h testHandler) InstagramReaction(ctx context.Context, object handler.Object, entry handler.Entry, reaction handler.MessagingReaction) error {
+	return h.run(ctx)
+}
+
+// InstagramSeen implements handler.InstagramSeenHandler.
+func (h testHandler) InstagramSeen(ctx context.Context, object handler.Object, entry handler.Entry, seen handler.MessagingSeen) error {
+	return h.run(ctx)
+}
+
+// PageMessage implements handler.PageMessageHandler.
+func (h testHandler) PageMessage(ctx context.Context, object handler.Object, entry handler.Entry, message handler.MessagingMessage) error {
+	return h.run(ctx)
+}
+
+// PagePostback implements handler.PagePostbackHandler.
+func (h testHandler) PagePostback(ctx context.Context, object handler.Object, entry handler.Entry, postback handler.MessagingPostback) error {
+	return h.run(ctx)
+}
+
+// PageReferral implements handler.PageReferralHandler.
+func (h testHandler) PageReferral(ctx context.Context, object handler.Object, entry handler.Entry, referral handler.MessagingReferral) error {
+	return h.run(ctx)
+}
+
+// PageReaction implements handler.PageReactionHandler.
+func (h testHandler) PageReaction(ctx context.Context, object handler.Object, entry handler.Entry, reaction handler.MessagingReaction) error {
+	return h.run(ctx)
+}
+
+// PageSeen implements handler.PageSeenHandler.
+func (h testHandler) PageSeen(ctx context.Context, object handler.Object, entry handler.Entry, seen handler.MessagingSeen) error {
+	return h.run(ctx)
+}
+
+// WhatsAppMessage implements handler.WhatsAppMessageHandler.
+func (h testHandler) WhatsAppMessage(ctx context.Context, object handler.Object, entry handler.Entry, value handler.WhatsAppMessages, message handler.WhatsAppMessage) error {
+	return h.run(ctx)
+}
+
+// WhatsAppStatus implements handler.WhatsAppStatusHandler.
+func (h testHandler) WhatsAppStatus(ctx context.Context, object handler.Object, entry handler.Entry, value handler.WhatsAppMessages, status handler.WhatsAppStatus) error {
+	return h.run(ctx)
+}
+
 var _ handler.InstagramHandler = (*testHandler)(nil)
+var _ handler.PageHandler = (*testHandler)(nil)
+var _ handler.WhatsAppHandler = (*testHandler)(nil)
 
 type hookScenario struct {
 	name             string
@@ -57,6 +114,8 @@ type hookScenario struct {
 	url              string
 	headers          map[string]string
 	options          func(scenario *hookScenario) []handler.Option
+	httpOptions      []handler.HTTPOption
+	asyncOptions     func(scenario *hookScenario) []handler.AsyncOption
 	body             io.Reader
 	bodyBytes        []byte
 	expected         interface{}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	gometawebhooks "github.com/pnmcosta/go-meta-webhooks"
+)
+
+// Writes the HTTP response for errors raised while handling Meta Webhooks requests
+type ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+
+// HTTPOption is a configuration option for the HTTP handler
+type HTTPOption func(*httpHandler) error
+
+// HTTPOptions is a namespace var for HTTP handler configuration options
+var HTTPOptions = HTTPHandlerOptions{}
+
+// HTTPHandlerOptions is a namespace for HTTP handler configuration option methods
+type HTTPHandlerOptions struct{}
+
+// Sets a custom error response writer, defaults to DefaultErrorHandler
+func (HTTPHandlerOptions) ErrorHandler(fn ErrorHandlerFunc) HTTPOption {
+	return func(h *httpHandler) error {
+		h.errorHandler = fn
+		return nil
+	}
+}
+
+var _ http.Handler = (*httpHandler)(nil)
+
+type httpHandler struct {
+	hooks        DefaultHandler
+	errorHandler ErrorHandlerFunc
+	dispatcher   Dispatcher
+	parser       RequestParser
+}
+
+// Creates and returns an http.Handler implementing the full Meta Webhooks endpoint, GET requests are
+// verified and answered with the challenge, POST requests are verified, validated, parsed and handled.
+func NewHTTPHandler(hooks DefaultHandler, opts ...HTTPOption) (*httpHandler, error) {
+	h := &httpHandler{
+		hooks:        hooks,
+		errorHandler: DefaultErrorHandler,
+	}
+
+	for _, opt := range opts {
+		if err := opt(h); err != nil {
+			return nil, wrapErr(err, gometawebhooks.ErrApplyingOption)
+		}
+	}
+
+	if h.dispatcher != nil {
+		parser, ok := hooks.(RequestParser)
+		if !ok {
+			return nil, ErrRequestParser
+		}
+		h.parser = parser
+	}
+
+	return h, nil
+}
+
+func (h httpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		challenge, err := h.hooks.HandleVerify(r)
+		if err != nil {
+			h.errorHandler(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, challenge)
+	case http.MethodPost:
+		if err := h.handle(r); err != nil {
+			h.errorHandler(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	default:
+		h.errorHandler(w, r, ErrInvalidHTTPMethod)
+	}
+}
+
+func (h httpHandler) handle(r *http.Request) error {
+	if h.dispatcher == nil {
+		_, _, err := h.hooks.HandleRequest(r.Context(), r)
+		return err
+	}
+
+	event, _, err := h.parser.ParseRequest(r)
+	if err != nil {
+		return err
+	}
+
+	if err := h.dispatcher.Dispatch(r.Context(), event); err != nil {
+		// accepts Meta's redelivery of the event which failed to be dispatched
+		if forgetter, ok := h.hooks.(interface {
+			ForgetSignature(context.Context, http.Header) error
+		}); ok {
+			_ = forgetter.ForgetSignature(context.WithoutCancel(r.Context()), r.Header)
+		}
+		return err
+	}
+	return nil
+}
+
+// Maps errors raised while handling Meta Webhooks requests to HTTP status codes
+func StatusCode(err error) int {
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, ErrInvalidHTTPMethod):
+		return http.StatusMethodNotAllowed
+	case errors.Is(err, gometawebhooks.ErrMissingHubSignatureHeader),
+		errors.Is(err, gometawebhooks.ErrHMACVerificationFailed):
+		return http.StatusUnauthorized
+	case errors.Is(err, gometawebhooks.ErrVerifyTokenFailed):
+		return http.StatusForbidden
+	case errors.Is(err, gometawebhooks.ErrPayloadTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, gometawebhooks.ErrUnsupportedContentEncoding):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, ErrReadBodyPayload),
+		errors.Is(err, gometawebhooks.ErrParsingPayload),
+		errors.Is(err, gometawebhooks.ErrInvalidPayload),
+		errors.Is(err, gometawebhooks.ErrStaleEvent):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrNoRoute):
+		return http.StatusNotFound
+	case errors.Is(err, ErrDispatcherClosed):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Writes the status code mapped by StatusCode and its status text
+func DefaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	code := StatusCode(err)
+	if code == http.StatusMethodNotAllowed {
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+	}
+	http.Error(w, http.StatusText(code), code)
+}
@@ -0,0 +1,134 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+func TestServeHTTP(t *testing.T) {
+	t.Parallel()
+	scenarios := []hookScenario{
+		{
+			name:     "invalid method",
+			method:   http.MethodPut,
+			expected: http.StatusMethodNotAllowed,
+		},
+		{
+			name:     "invalid verify_token",
+			url:      "/webhooks/meta/?hub.mode=subscribe&hub.verify_token=123&hub.challenge=challenge_response",
+			method:   http.MethodGet,
+			expected: http.StatusForbidden,
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.Token("meta_app_webhook_token"),
+				}
+			},
+		},
+		{
+			name:     "verifies",
+			url:      "/webhooks/meta/?hub.mode=subscribe&hub.verify_token=meta_app_webhook_token&hub.challenge=challenge_response",
+			method:   http.MethodGet,
+			expected: http.StatusOK,
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.Token("meta_app_webhook_token"),
+				}
+			},
+		},
+		{
+			name:   "invalid signature",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"X-Hub-Signature-256": "sha256=1",
+			},
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.Secret("very_secret"),
+				}
+			},
+			body:     strings.NewReader(`{}`),
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name:   "invalid payload",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+				}
+			},
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:   "handler error",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"mentions","value":{"media_id":"999"}}]}]}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramMentionHandler(testHandler{func(ctx context.Context) error {
+						return errors.New("failed")
+					}}),
+				}
+			},
+			expected: http.StatusInternalServerError,
+		},
+		{
+			name:   "custom error handler",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+				}
+			},
+			httpOptions: []handler.HTTPOption{
+				handler.HTTPOptions.ErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+					w.WriteHeader(http.StatusTeapot)
+				}),
+			},
+			expected: http.StatusTeapot,
+		},
+		{
+			name:   "handles",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"mentions","value":{"media_id":"999"}}]}]}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramMentionHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("mention")
+						return nil
+					}}),
+				}
+			},
+			expected: http.StatusOK,
+			expectedHandlers: map[string]int{
+				"mention": 1,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.test(t, func(t *testing.T) {
+			hooks, req := scenario.setup(t)
+
+			h, err := handler.NewHTTPHandler(hooks, scenario.httpOptions...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			scenario.assert(t, rec.Code, scenario.bodyBytes, nil)
+		})
+	}
+}
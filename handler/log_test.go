@@ -0,0 +1,185 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *logBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestLogger(t *testing.T) {
+	t.Parallel()
+
+	const payload = `{"object":"instagram","entry":[{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"890","text":"secret text"}}]}]}`
+
+	scenarios := []struct {
+		name        string
+		options     []handler.Option
+		headers     map[string]string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name: "signature failure",
+			options: []handler.Option{
+				handler.Options.Secret("very_secret"),
+			},
+			headers: map[string]string{
+				"X-Hub-Signature-256": genHmac("other_secret", payload),
+			},
+			contains: []string{
+				`"level":"WARN","msg":"verify payload failed"`,
+			},
+			notContains: []string{
+				"dispatched",
+			},
+		},
+		{
+			name: "rotated secret",
+			options: []handler.Option{
+				handler.Options.CompileSchema(),
+				handler.Options.Secrets("new_secret", "old_secret"),
+			},
+			headers: map[string]string{
+				"X-Hub-Signature-256": genHmac("old_secret", payload),
+			},
+			contains: []string{
+				`"msg":"verify payload","header":"X-Hub-Signature-256","secret_index":1`,
+			},
+		},
+		{
+			name: "dispatch steps",
+			options: []handler.Option{
+				handler.Options.CompileSchema(),
+				handler.Options.InstagramMessageHandler(testHandler{func(ctx context.Context) error {
+					return nil
+				}}),
+			},
+			contains: []string{
+				`"msg":"validate payload"`,
+				`"msg":"parse payload","object":"instagram","entries":1`,
+				`"msg":"dispatched","object":"instagram","entry_id":"123","kind":"message","sender_id":"567"`,
+			},
+			notContains: []string{
+				"secret text",
+			},
+		},
+		{
+			name: "handler failure",
+			options: []handler.Option{
+				handler.Options.CompileSchema(),
+				handler.Options.LogPayloads(true),
+			},
+			contains: []string{
+				`"level":"ERROR","msg":"handler failed","object":"instagram","entry_id":"123","kind":"message"`,
+				"secret text",
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			logs := &logBuffer{}
+			logger := slog.New(slog.NewJSONHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			hooks, err := handler.New(append(scenario.options, handler.Options.Logger(logger))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/meta", strings.NewReader(payload))
+			for k, v := range scenario.headers {
+				req.Header.Set(k, v)
+			}
+
+			_, _, _ = hooks.HandleRequest(context.Background(), req)
+
+			output := logs.String()
+			for _, s := range scenario.contains {
+				if !strings.Contains(output, s) {
+					t.Errorf("Expected logs to contain %s, but got %s", s, output)
+				}
+			}
+			for _, s := range scenario.notContains {
+				if strings.Contains(output, s) {
+					t.Errorf("Expected logs not to contain %s, but got %s", s, output)
+				}
+			}
+		})
+	}
+}
+
+type requestIdKey struct{}
+
+// Adds the request id of ctx to records
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id, ok := ctx.Value(requestIdKey{}).(string); ok {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func TestLoggerContext(t *testing.T) {
+	t.Parallel()
+
+	const payload = `{"object":"instagram","entry":[{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"890"}}]}]}`
+
+	logs := &logBuffer{}
+	logger := slog.New(contextHandler{slog.NewJSONHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug})})
+
+	hooks, err := handler.New(
+		handler.Options.Logger(logger),
+		handler.Options.InstagramMessageHandler(testHandler{func(ctx context.Context) error {
+			return nil
+		}}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.WithValue(context.Background(), requestIdKey{}, "abc")
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/meta", strings.NewReader(payload)).WithContext(ctx)
+
+	if _, _, err := hooks.HandleRequest(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+
+	output := strings.TrimSpace(logs.String())
+	for _, msg := range []string{"validate payload", "parse payload", "dispatched"} {
+		if !strings.Contains(output, `"msg":"`+msg+`"`) {
+			t.Errorf("Expected logs to contain %s, but got %s", msg, output)
+		}
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.Contains(line, `"request_id":"abc"`) {
+			t.Errorf("Expected log line with request id, but got %s", line)
+		}
+	}
+}
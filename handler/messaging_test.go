@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	gometawebhooks "github.com/pnmcosta/go-meta-webhooks"
 	"github.com/pnmcosta/go-meta-webhooks/handler"
 )
 
@@ -677,6 +678,181 @@ func TestHandleMessaging(t *testing.T) {
 				"message": 1,
 			},
 		},
+		{
+			name:   "reaction",
+			method: http.MethodPost,
+			body: strings.NewReader(`{
+				"object": "instagram",
+				"entry": [
+				  {
+					"id": "123",
+					"time": 1569262486134,
+					"messaging": [
+					  {
+						"sender": {
+						  "id": "567"
+						},
+						"recipient": {
+						  "id": "123"
+						},
+						"timestamp": 1569262485349,
+						"reaction": {
+							"mid": "MESSAGE-ID",
+							"action": "react",
+							"reaction": "love",
+							"emoji": "\u2764\uFE0F"
+						}
+					  }
+					]
+				  }
+				]
+			  }`),
+			expected: handler.Event{
+				Object: handler.Instagram,
+				Entry: []handler.Entry{{
+					Id:   "123",
+					Time: 1569262486134,
+					Messaging: []handler.Messaging{{
+						Type: handler.MessagingReaction{
+							MessagingHeader: handler.MessagingHeader{
+								Sender: struct {
+									Id string "json:\"id\""
+								}{
+									Id: "567",
+								},
+								Recipient: struct {
+									Id string "json:\"id\""
+								}{
+									Id: "123",
+								},
+								Timestamp: 1569262485349,
+							},
+							Reaction: handler.Reaction{
+								Id:       "MESSAGE-ID",
+								Action:   "react",
+								Reaction: "love",
+								Emoji:    "\u2764\uFE0F",
+							},
+						},
+					}},
+				}},
+			},
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramReactionHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("reaction")
+						return nil
+					}}),
+				}
+			},
+			expectedHandlers: map[string]int{
+				"reaction": 1,
+			},
+		},
+		{
+			name:   "seen",
+			method: http.MethodPost,
+			body: strings.NewReader(`{
+				"object": "instagram",
+				"entry": [
+				  {
+					"id": "123",
+					"time": 1569262486134,
+					"messaging": [
+					  {
+						"sender": {
+						  "id": "567"
+						},
+						"recipient": {
+						  "id": "123"
+						},
+						"timestamp": 1569262485349,
+						"read": {
+							"mid": "MESSAGE-ID"
+						}
+					  }
+					]
+				  }
+				]
+			  }`),
+			expected: handler.Event{
+				Object: handler.Instagram,
+				Entry: []handler.Entry{{
+					Id:   "123",
+					Time: 1569262486134,
+					Messaging: []handler.Messaging{{
+						Type: handler.MessagingSeen{
+							MessagingHeader: handler.MessagingHeader{
+								Sender: struct {
+									Id string "json:\"id\""
+								}{
+									Id: "567",
+								},
+								Recipient: struct {
+									Id string "json:\"id\""
+								}{
+									Id: "123",
+								},
+								Timestamp: 1569262485349,
+							},
+							Read: handler.Read{
+								Id: "MESSAGE-ID",
+							},
+						},
+					}},
+				}},
+			},
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramMessagingHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("seen")
+						return nil
+					}}),
+				}
+			},
+			expectedHandlers: map[string]int{
+				"seen": 1,
+			},
+		},
+		{
+			name:   "seen handler not defined",
+			method: http.MethodPost,
+			body: strings.NewReader(`{
+				"object": "instagram",
+				"entry": [
+				  {
+					"id": "123",
+					"time": 1569262486134,
+					"messaging": [
+					  {
+						"sender": {
+						  "id": "567"
+						},
+						"recipient": {
+						  "id": "123"
+						},
+						"timestamp": 1569262485349,
+						"read": {
+							"mid": "MESSAGE-ID"
+						}
+					  }
+					]
+				  }
+				]
+			  }`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramMessageHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("message")
+						return nil
+					}}),
+				}
+			},
+			expectErr: gometawebhooks.ErrInstagramSeenHandlerNotDefined,
+		},
 	}
 
 	for _, scenario := range scenarios {
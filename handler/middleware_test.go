@@ -0,0 +1,121 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+var errUnauthorized = errors.New("unauthorized")
+
+func traceMiddleware(scenario *hookScenario, name string) handler.Middleware {
+	return func(next handler.DispatchFunc) handler.DispatchFunc {
+		return func(ctx context.Context, object handler.Object, entry handler.Entry, item interface{}) error {
+			scenario.trigger(fmt.Sprintf("%s:before:%T", name, item))
+			err := next(ctx, object, entry, item)
+			scenario.trigger(name + ":after")
+			return err
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+	scenarios := []struct {
+		hookScenario
+		order []string
+	}{
+		{
+			hookScenario: hookScenario{
+				name:   "composes in order",
+				method: http.MethodPost,
+				body:   strings.NewReader(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"mentions","value":{"media_id":"999"}}]}]}`),
+				options: func(scenario *hookScenario) []handler.Option {
+					return []handler.Option{
+						handler.Options.CompileSchema(),
+						handler.Options.Use(traceMiddleware(scenario, "first")),
+						handler.Options.Use(traceMiddleware(scenario, "second")),
+						handler.Options.InstagramMentionHandler(testHandler{func(ctx context.Context) error {
+							scenario.trigger("mention")
+							return nil
+						}}),
+					}
+				},
+			},
+			order: []string{
+				"first:before:gometawebhooks.Mention",
+				"second:before:gometawebhooks.Mention",
+				"mention",
+				"second:after",
+				"first:after",
+			},
+		},
+		{
+			hookScenario: hookScenario{
+				name:   "typed messaging item",
+				method: http.MethodPost,
+				body:   strings.NewReader(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"890"}}]}]}`),
+				options: func(scenario *hookScenario) []handler.Option {
+					return []handler.Option{
+						handler.Options.CompileSchema(),
+						handler.Options.Use(traceMiddleware(scenario, "trace")),
+						handler.Options.InstagramMessageHandler(testHandler{func(ctx context.Context) error {
+							scenario.trigger("message")
+							return nil
+						}}),
+					}
+				},
+			},
+			order: []string{
+				"trace:before:gometawebhooks.MessagingMessage",
+				"message",
+				"trace:after",
+			},
+		},
+		{
+			hookScenario: hookScenario{
+				name:   "short circuits",
+				method: http.MethodPost,
+				body:   strings.NewReader(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"mentions","value":{"media_id":"999"}}]}]}`),
+				options: func(scenario *hookScenario) []handler.Option {
+					return []handler.Option{
+						handler.Options.CompileSchema(),
+						handler.Options.Use(func(next handler.DispatchFunc) handler.DispatchFunc {
+							return func(ctx context.Context, object handler.Object, entry handler.Entry, item interface{}) error {
+								return errUnauthorized
+							}
+						}),
+						handler.Options.InstagramMentionHandler(testHandler{func(ctx context.Context) error {
+							scenario.trigger("mention")
+							return nil
+						}}),
+					}
+				},
+				expectErr: errUnauthorized,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.test(t, func(t *testing.T) {
+			hooks, req := scenario.setup(t)
+
+			ctx, cancel := context.WithTimeout(context.Background(), scenario.timeout)
+			defer cancel()
+
+			_, payload, err := hooks.HandleRequest(ctx, req)
+
+			scenario.assert(t, nil, payload, err)
+
+			if scenario.order != nil && !slices.Equal(scenario.handled, scenario.order) {
+				t.Errorf("Expected order %v, but got %v", scenario.order, scenario.handled)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+// Records handled message ids per sender
+type conversationHandler struct {
+	mu      *sync.Mutex
+	handled map[string][]string
+}
+
+// InstagramMessage implements handler.InstagramMessageHandler.
+func (h conversationHandler) InstagramMessage(ctx context.Context, object handler.Object, entry handler.Entry, message handler.MessagingMessage) error {
+	// later messages of a conversation finish first unless dispatched in order
+	time.Sleep(time.Duration(5-len(message.Message.Id)) * time.Millisecond)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handled[message.Sender.Id] = append(h.handled[message.Sender.Id], message.Message.Id)
+	return nil
+}
+
+func TestOrderedConversations(t *testing.T) {
+	t.Parallel()
+
+	scenario := hookScenario{
+		method: http.MethodPost,
+		body: strings.NewReader(`{"object":"instagram","entry":[` +
+			`{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"A"},"recipient":{"id":"123"},"timestamp":3,"message":{"mid":"aaa"}},{"sender":{"id":"B"},"recipient":{"id":"123"},"timestamp":1,"message":{"mid":"b"}}]},` +
+			`{"id":"123","time":1569262486135,"messaging":[{"sender":{"id":"A"},"recipient":{"id":"123"},"timestamp":1,"message":{"mid":"a"}},{"sender":{"id":"A"},"recipient":{"id":"123"},"timestamp":2,"message":{"mid":"aa"}}]}]}`),
+		expected: map[string][]string{
+			"A": {"a", "aa", "aaa"},
+			"B": {"b"},
+		},
+		timeout: time.Second,
+	}
+
+	h := conversationHandler{&sync.Mutex{}, map[string][]string{}}
+	scenario.options = func(scenario *hookScenario) []handler.Option {
+		return []handler.Option{
+			handler.Options.CompileSchema(),
+			handler.Options.OrderedConversations(true),
+			handler.Options.InstagramMessageHandler(h),
+		}
+	}
+
+	hooks, req := scenario.setup(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), scenario.timeout)
+	defer cancel()
+
+	_, payload, err := hooks.HandleRequest(ctx, req)
+
+	scenario.assert(t, h.handled, payload, err)
+}
@@ -0,0 +1,528 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	gometawebhooks "github.com/pnmcosta/go-meta-webhooks"
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+func TestHandlePage(t *testing.T) {
+	t.Parallel()
+	scenarios := []hookScenario{
+		{
+			name:   "message handler not defined",
+			method: http.MethodPost,
+			body: strings.NewReader(`{
+				"object": "page",
+				"entry": [
+				  {
+					"id": "123",
+					"time": 1569262486134,
+					"messaging": [
+					  {
+						"sender": {
+						  "id": "567"
+						},
+						"recipient": {
+						  "id": "123"
+						},
+						"timestamp": 1569262485349,
+						"message": {
+						  "mid": "890",
+						  "text": "Text in message"
+						}
+					  }
+					]
+				  }
+				]
+			  }`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("instagram")
+						return nil
+					}}),
+				}
+			},
+			expectErr: gometawebhooks.ErrPageMessageHandlerNotDefined,
+		},
+		{
+			name:   "comments field",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{"object":"page","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"comments","value":{"id":"17865799348089039","text":"This is an example."}}]}]}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("instagram")
+						return nil
+					}}),
+				}
+			},
+			expectErr: gometawebhooks.ErrChangesFieldNotImplemented,
+		},
+		{
+			name:   "seen handler not defined",
+			method: http.MethodPost,
+			body: strings.NewReader(`{
+				"object": "page",
+				"entry": [
+				  {
+					"id": "123",
+					"time": 1569262486134,
+					"messaging": [
+					  {
+						"sender": {
+						  "id": "567"
+						},
+						"recipient": {
+						  "id": "123"
+						},
+						"timestamp": 1569262485349,
+						"read": {
+						  "watermark": 1569262485000
+						}
+					  }
+					]
+				  }
+				]
+			  }`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.PageMessageHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("message")
+						return nil
+					}}),
+				}
+			},
+			expectErr: gometawebhooks.ErrPageSeenHandlerNotDefined,
+		},
+		{
+			name:   "text message",
+			method: http.MethodPost,
+			body: strings.NewReader(`{
+				"object": "page",
+				"entry": [
+				  {
+					"id": "123",
+					"time": 1569262486134,
+					"messaging": [
+					  {
+						"sender": {
+						  "id": "567"
+						},
+						"recipient": {
+						  "id": "123"
+						},
+						"timestamp": 1569262485349,
+						"message": {
+						  "mid": "890",
+						  "text": "Text in message"
+						}
+					  }
+					]
+				  }
+				]
+			  }`),
+			expected: handler.Event{
+				Object: handler.Page,
+				Entry: []handler.Entry{{
+					Id:   "123",
+					Time: 1569262486134,
+					Messaging: []handler.Messaging{{
+						Type: handler.MessagingMessage{
+							MessagingHeader: handler.MessagingHeader{
+								Sender: struct {
+									Id string "json:\"id\""
+								}{
+									Id: "567",
+								},
+								Recipient: struct {
+									Id string "json:\"id\""
+								}{
+									Id: "123",
+								},
+								Timestamp: 1569262485349,
+							},
+							Message: handler.Message{
+								Id:   "890",
+								Text: "Text in message",
+							},
+						},
+					}},
+				}},
+			},
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.PageMessageHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("message")
+						return nil
+					}}),
+				}
+			},
+			expectedHandlers: map[string]int{
+				"message": 1,
+			},
+		},
+		{
+			name:   "handles messaging",
+			method: http.MethodPost,
+			body: strings.NewReader(`{
+				"object": "page",
+				"entry": [
+				  {
+					"id": "123",
+					"time": 1569262486134,
+					"messaging": [
+					  {
+						"sender": {
+						  "id": "567"
+						},
+						"recipient": {
+						  "id": "123"
+						},
+						"timestamp": 1569262485349,
+						"message": {
+						  "mid": "890",
+						  "text": "Text in message"
+						}
+					  },
+					  {
+						"sender": {
+						  "id": "567"
+						},
+						"recipient": {
+						  "id": "123"
+						},
+						"timestamp": 1569262485349,
+						"postback": {
+							"mid":"MESSAGE-ID",
+							"title": "GET_STARTED",
+							"payload": "GET_STARTED_PAYLOAD"
+						}
+					  },
+					  {
+						"sender": {
+						  "id": "567"
+						},
+						"recipient": {
+						  "id": "123"
+						},
+						"timestamp": 1569262485349,
+						"referral": {
+							"ref": "REF-DATA",
+							"source": "SHORTLINK",
+							"type": "OPEN_THREAD"
+						}
+					  },
+					  {
+						"sender": {
+						  "id": "567"
+						},
+						"recipient": {
+						  "id": "123"
+						},
+						"timestamp": 1569262485349,
+						"reaction": {
+							"mid": "890",
+							"action": "react",
+							"reaction": "love",
+							"emoji": "\u2764\uFE0F"
+						}
+					  },
+					  {
+						"sender": {
+						  "id": "567"
+						},
+						"recipient": {
+						  "id": "123"
+						},
+						"timestamp": 1569262485349,
+						"read": {
+							"watermark": 1569262485000
+						}
+					  }
+					]
+				  }
+				]
+			  }`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("instagram")
+						return nil
+					}}),
+					handler.Options.PageHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("page")
+						return nil
+					}}),
+				}
+			},
+			expected: handler.Event{
+				Object: handler.Page,
+				Entry: []handler.Entry{{
+					Id:   "123",
+					Time: 1569262486134,
+					Messaging: []handler.Messaging{{
+						Type: handler.MessagingMessage{
+							MessagingHeader: handler.MessagingHeader{
+								Sender: struct {
+									Id string "json:\"id\""
+								}{
+									Id: "567",
+								},
+								Recipient: struct {
+									Id string "json:\"id\""
+								}{
+									Id: "123",
+								},
+								Timestamp: 1569262485349,
+							},
+							Message: handler.Message{
+								Id:   "890",
+								Text: "Text in message",
+							},
+						},
+					}, {
+						Type: handler.MessagingPostback{
+							MessagingHeader: handler.MessagingHeader{
+								Sender: struct {
+									Id string "json:\"id\""
+								}{
+									Id: "567",
+								},
+								Recipient: struct {
+									Id string "json:\"id\""
+								}{
+									Id: "123",
+								},
+								Timestamp: 1569262485349,
+							},
+							Postback: handler.Postback{
+								Id:      "MESSAGE-ID",
+								Title:   "GET_STARTED",
+								Payload: "GET_STARTED_PAYLOAD",
+							},
+						},
+					}, {
+						Type: handler.MessagingReferral{
+							MessagingHeader: handler.MessagingHeader{
+								Sender: struct {
+									Id string "json:\"id\""
+								}{
+									Id: "567",
+								},
+								Recipient: struct {
+									Id string "json:\"id\""
+								}{
+									Id: "123",
+								},
+								Timestamp: 1569262485349,
+							},
+							Referral: handler.Referral{
+								Ref:    "REF-DATA",
+								Source: "SHORTLINK",
+								Type:   "OPEN_THREAD",
+							},
+						},
+					}, {
+						Type: handler.MessagingReaction{
+							MessagingHeader: handler.MessagingHeader{
+								Sender: struct {
+									Id string "json:\"id\""
+								}{
+									Id: "567",
+								},
+								Recipient: struct {
+									Id string "json:\"id\""
+								}{
+									Id: "123",
+								},
+								Timestamp: 1569262485349,
+							},
+							Reaction: handler.Reaction{
+								Id:       "890",
+								Action:   "react",
+								Reaction: "love",
+								Emoji:    "\u2764\uFE0F",
+							},
+						},
+					}, {
+						Type: handler.MessagingSeen{
+							MessagingHeader: handler.MessagingHeader{
+								Sender: struct {
+									Id string "json:\"id\""
+								}{
+									Id: "567",
+								},
+								Recipient: struct {
+									Id string "json:\"id\""
+								}{
+									Id: "123",
+								},
+								Timestamp: 1569262485349,
+							},
+							Read: handler.Read{
+								Watermark: 1569262485000,
+							},
+						},
+					}},
+				}},
+			},
+			expectedHandlers: map[string]int{
+				"page": 5,
+			},
+		},
+		{
+			name:   "messenger attachments",
+			method: http.MethodPost,
+			body: strings.NewReader(`{
+				"object": "page",
+				"entry": [
+				  {
+					"id": "123",
+					"time": 1569262486134,
+					"messaging": [
+					  {
+						"sender": {
+						  "id": "123"
+						},
+						"recipient": {
+						  "id": "567"
+						},
+						"timestamp": 1569262485349,
+						"message": {
+						  "mid": "890",
+						  "is_echo": true,
+						  "app_id": 1517776481860111,
+						  "attachments": [
+							{
+							  "type": "template",
+							  "payload": {
+								"template_type": "button",
+								"text": "Pick one",
+								"buttons": [
+								  {
+									"type": "postback",
+									"title": "Start",
+									"payload": "START"
+								  }
+								]
+							  }
+							}
+						  ]
+						}
+					  },
+					  {
+						"sender": {
+						  "id": "567"
+						},
+						"recipient": {
+						  "id": "123"
+						},
+						"timestamp": 1569262485349,
+						"message": {
+						  "mid": "891",
+						  "attachments": [
+							{
+							  "type": "location",
+							  "payload": {
+								"coordinates": {
+								  "lat": 38.72,
+								  "long": -9.14
+								}
+							  }
+							}
+						  ]
+						}
+					  }
+					]
+				  }
+				]
+			  }`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.IgnoreEchoMessages(true),
+					handler.Options.PageMessageHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("message")
+						return nil
+					}}),
+				}
+			},
+			expected: handler.Event{
+				Object: handler.Page,
+				Entry: []handler.Entry{{
+					Id:   "123",
+					Time: 1569262486134,
+					Messaging: []handler.Messaging{{
+						Type: handler.MessagingMessage{
+							MessagingHeader: handler.MessagingHeader{
+								Sender: struct {
+									Id string "json:\"id\""
+								}{
+									Id: "123",
+								},
+								Recipient: struct {
+									Id string "json:\"id\""
+								}{
+									Id: "567",
+								},
+								Timestamp: 1569262485349,
+							},
+							Message: handler.Message{
+								Id:          "890",
+								IsEcho:      true,
+								Attachments: []handler.Attachment{{Type: "template"}},
+							},
+						},
+					}, {
+						Type: handler.MessagingMessage{
+							MessagingHeader: handler.MessagingHeader{
+								Sender: struct {
+									Id string "json:\"id\""
+								}{
+									Id: "567",
+								},
+								Recipient: struct {
+									Id string "json:\"id\""
+								}{
+									Id: "123",
+								},
+								Timestamp: 1569262485349,
+							},
+							Message: handler.Message{
+								Id:          "891",
+								Attachments: []handler.Attachment{{Type: "location"}},
+							},
+						},
+					}},
+				}},
+			},
+			expectedHandlers: map[string]int{
+				"message": 1,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.test(t, func(t *testing.T) {
+			hooks, req := scenario.setup(t)
+
+			ctx, cancel := context.WithTimeout(context.Background(), scenario.timeout)
+			defer cancel()
+
+			result, payload, err := hooks.HandleRequest(ctx, req)
+
+			scenario.assert(t, result, payload, err)
+		})
+	}
+}
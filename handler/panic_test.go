@@ -0,0 +1,186 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+var errPanic = errors.New("panic value")
+
+func TestPanicRecovery(t *testing.T) {
+	t.Parallel()
+	scenarios := []hookScenario{
+		{
+			name:   "change handler",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"mentions","value":{"media_id":"999"}}]}]}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramMentionHandler(testHandler{func(ctx context.Context) error {
+						panic("boom")
+					}}),
+				}
+			},
+			expected: &handler.HandlerPanicError{
+				Object:  handler.Instagram,
+				EntryId: "123",
+				Kind:    "mentions",
+				Value:   "boom",
+			},
+		},
+		{
+			name:   "messaging handler",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"890"}}]}]}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramMessageHandler(testHandler{func(ctx context.Context) error {
+						panic(errPanic)
+					}}),
+				}
+			},
+			expected: &handler.HandlerPanicError{
+				Object:  handler.Instagram,
+				EntryId: "123",
+				Kind:    "message",
+				Value:   errPanic,
+			},
+		},
+		{
+			name:   "whatsapp handler",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{"object":"whatsapp_business_account","entry":[{"id":"WABA_ID","changes":[{"field":"messages","value":{"messaging_product":"whatsapp","metadata":{"phone_number_id":"123456123"},"statuses":[{"id":"wamid.ID","status":"sent","timestamp":"1669233778"}]}}]}]}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.WhatsAppStatusHandler(testHandler{func(ctx context.Context) error {
+						panic("boom")
+					}}),
+				}
+			},
+			expected: &handler.HandlerPanicError{
+				Object:  handler.WhatsAppBusinessAccount,
+				EntryId: "WABA_ID",
+				Kind:    "statuses",
+				Value:   "boom",
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.test(t, func(t *testing.T) {
+			hooks, req := scenario.setup(t)
+
+			ctx, cancel := context.WithTimeout(context.Background(), scenario.timeout)
+			defer cancel()
+
+			_, payload, err := hooks.HandleRequest(ctx, req)
+
+			var panicErr *handler.HandlerPanicError
+			if !errors.As(err, &panicErr) {
+				t.Fatalf("Expected *HandlerPanicError, but got %v", err)
+			}
+
+			if len(panicErr.Stack) == 0 {
+				t.Errorf("Expected stack trace, but got none")
+			}
+			panicErr.Stack = nil
+
+			if value, ok := panicErr.Value.(error); ok && !errors.Is(err, value) {
+				t.Errorf("Expected error %v, but got %v", value, err)
+			}
+
+			scenario.assert(t, panicErr, payload, nil)
+		})
+	}
+}
+
+func TestPanicRepanics(t *testing.T) {
+	t.Parallel()
+
+	event := handler.Event{
+		Object: handler.Instagram,
+		Entry: []handler.Entry{{
+			Id:   "123",
+			Time: 1569262486134,
+			Changes: []handler.Change{{
+				Field: "mentions",
+				Value: handler.Mention{MediaID: "999"},
+			}, {
+				Field: "story_insights",
+				Value: handler.StoryInsights{MediaID: "999"},
+			}},
+		}},
+	}
+
+	scenarios := []struct {
+		name    string
+		options []handler.Option
+	}{
+		{
+			name: "handler",
+			options: []handler.Option{
+				handler.Options.InstagramMentionHandler(testHandler{func(ctx context.Context) error {
+					panic("boom")
+				}}),
+				handler.Options.InstagramStoryInsightsHandler(testHandler{func(ctx context.Context) error {
+					return nil
+				}}),
+			},
+		},
+		{
+			name: "after a sibling error",
+			options: []handler.Option{
+				handler.Options.InstagramMentionHandler(testHandler{func(ctx context.Context) error {
+					<-ctx.Done()
+					panic("boom")
+				}}),
+				handler.Options.InstagramStoryInsightsHandler(testHandler{func(ctx context.Context) error {
+					return errors.New("plain")
+				}}),
+			},
+		},
+		{
+			name: "after the handler timeout",
+			options: []handler.Option{
+				handler.Options.ContinueOnError(true),
+				handler.Options.HandlerTimeoutFor("mentions", time.Millisecond),
+				handler.Options.InstagramMentionHandler(testHandler{func(ctx context.Context) error {
+					time.Sleep(10 * time.Millisecond)
+					panic("boom")
+				}}),
+				handler.Options.InstagramStoryInsightsHandler(testHandler{func(ctx context.Context) error {
+					time.Sleep(100 * time.Millisecond)
+					return nil
+				}}),
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			hooks, err := handler.New(append(scenario.options, handler.Options.RecoverPanics(false))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				if _, ok := recover().(*handler.HandlerPanicError); !ok {
+					t.Errorf("Expected *HandlerPanicError panic")
+				}
+			}()
+
+			_ = hooks.Handle(context.Background(), event)
+
+			t.Errorf("Expected Handle to panic")
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	gometawebhooks "github.com/pnmcosta/go-meta-webhooks"
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+func TestParsePayload(t *testing.T) {
+	t.Parallel()
+
+	message := func(text string) handler.Event {
+		value := handler.MessagingMessage{
+			Message: handler.Message{Id: "890", Text: text},
+		}
+		value.Sender.Id = "567"
+		value.Recipient.Id = "123"
+		value.Timestamp = 1569262485349
+
+		return handler.Event{
+			Object: handler.Instagram,
+			Entry: []handler.Entry{{
+				Id:        "123",
+				Time:      1569262486134,
+				Messaging: []handler.Messaging{{Type: value}},
+			}},
+		}
+	}
+	payload := func(text string) string {
+		return `{"object":"instagram","entry":[{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"890","text":` + text + `}}]}]}`
+	}
+
+	scenarios := []struct {
+		name      string
+		payload   string
+		expected  handler.Event
+		expectErr error
+	}{
+		{
+			name:     "escaped string",
+			payload:  payload(`"say \"hi\"\né"`),
+			expected: message("say \"hi\"\né"),
+		},
+		{
+			name:     "non-ASCII string",
+			payload:  payload(`"olá 👋"`),
+			expected: message("olá 👋"),
+		},
+		{
+			name:      "trailing data",
+			payload:   payload(`"hello"`) + `{}`,
+			expectErr: gometawebhooks.ErrParsingPayload,
+		},
+		{
+			name:      "truncated number",
+			payload:   `{"object":"instagram","entry":[{"id":"123","time":-`,
+			expectErr: gometawebhooks.ErrParsingPayload,
+		},
+		{
+			name:      "deep nesting",
+			payload:   strings.Repeat("[", 10001) + strings.Repeat("]", 10001),
+			expectErr: gometawebhooks.ErrParsingPayload,
+		},
+		{
+			name:      "mismatched type",
+			payload:   payload(`1`),
+			expectErr: gometawebhooks.ErrInvalidPayload,
+		},
+	}
+
+	hooks, err := handler.New(handler.Options.CompileSchema())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			var event handler.Event
+			err := hooks.ValidatePayload([]byte(scenario.payload))
+			if err == nil {
+				event, err = hooks.ParsePayload([]byte(scenario.payload))
+			}
+			if scenario.expectErr != nil {
+				if !errors.Is(err, scenario.expectErr) {
+					t.Errorf("Expected error %v, but got %v", scenario.expectErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(event, scenario.expected) {
+				t.Errorf("Expected %v, but got %v", scenario.expected, event)
+			}
+		})
+	}
+}
+
+func TestParsePayloadMatchesUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	hooks, err := handler.New(handler.Options.CompileSchema())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event, err := hooks.ParsePayload([]byte(benchPayload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected handler.Event
+	if err := json.Unmarshal([]byte(benchPayload), &expected); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(event, expected) {
+		t.Errorf("Expected %v, but got %v", expected, event)
+	}
+}
+
+func TestEntryTime(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name      string
+		payload   string
+		expectErr bool
+	}{
+		{
+			name:      "instagram changes require time",
+			payload:   `{"object":"instagram","entry":[{"id":"123","changes":[{"field":"mentions","value":{"media_id":"999"}}]}]}`,
+			expectErr: true,
+		},
+		{
+			name:      "page changes require time",
+			payload:   `{"object":"page","entry":[{"id":"123","changes":[{"field":"messages","value":{"messaging_product":"whatsapp"}}]}]}`,
+			expectErr: true,
+		},
+		{
+			name:    "whatsapp changes omit time",
+			payload: `{"object":"whatsapp_business_account","entry":[{"id":"123","changes":[{"field":"messages","value":{"messaging_product":"whatsapp","metadata":{"phone_number_id":"456"}}}]}]}`,
+		},
+	}
+
+	hooks, err := handler.New(
+		handler.Options.CompileSchema(),
+		handler.Options.RawChangeHandler(rawHandler{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			validateErr := hooks.ValidatePayload([]byte(scenario.payload))
+			_, parseErr := hooks.ParsePayload([]byte(scenario.payload))
+
+			if !scenario.expectErr {
+				if validateErr != nil || parseErr != nil {
+					t.Errorf("Expected no error, but got: %v, %v", validateErr, parseErr)
+				}
+				return
+			}
+
+			if !errors.Is(validateErr, gometawebhooks.ErrInvalidPayload) {
+				t.Errorf("Expected error %v, but got %v", gometawebhooks.ErrInvalidPayload, validateErr)
+			}
+			if !errors.Is(parseErr, gometawebhooks.ErrParsingEntry) {
+				t.Errorf("Expected error %v, but got %v", gometawebhooks.ErrParsingEntry, parseErr)
+			}
+		})
+	}
+}
+
+func TestUnmarshalUnsupported(t *testing.T) {
+	t.Parallel()
+
+	var change handler.Change
+	err := json.Unmarshal([]byte(`{"field":"unknown","value":{"id":"1"}}`), &change)
+	if !errors.Is(err, gometawebhooks.ErrChangesFieldNotImplemented) {
+		t.Errorf("Expected error %v, but got %v", gometawebhooks.ErrChangesFieldNotImplemented, err)
+	}
+
+	var messaging handler.Messaging
+	err = json.Unmarshal([]byte(`{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"unknown":{}}`), &messaging)
+	if !errors.Is(err, gometawebhooks.ErrMessagingTypeNotImplemented) {
+		t.Errorf("Expected error %v, but got %v", gometawebhooks.ErrMessagingTypeNotImplemented, err)
+	}
+
+	// preserved by ParsePayload once a raw handler is set
+	hooks, err := handler.New(handler.Options.RawHandler(rawHandler{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event, err := hooks.ParsePayload([]byte(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"unknown","value":{"id":"1"}}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw := string(event.Entry[0].Changes[0].Raw); raw != `{"id":"1"}` {
+		t.Errorf("Expected raw value %s, but got %s", `{"id":"1"}`, raw)
+	}
+}
@@ -0,0 +1,198 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	gometawebhooks "github.com/pnmcosta/go-meta-webhooks"
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+type rawHandler struct {
+	run func(ctx context.Context, raw json.RawMessage) error
+}
+
+// RawChange implements handler.RawChangeHandler.
+func (h rawHandler) RawChange(ctx context.Context, object handler.Object, entry handler.Entry, change handler.Change) error {
+	return h.run(ctx, change.Raw)
+}
+
+// RawMessaging implements handler.RawMessagingHandler.
+func (h rawHandler) RawMessaging(ctx context.Context, object handler.Object, entry handler.Entry, messaging handler.Messaging) error {
+	return h.run(ctx, messaging.Raw)
+}
+
+var _ handler.RawHandler = (*rawHandler)(nil)
+
+const unsupportedObjectPayload = `{"object":"unsupported","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"mentions","value":{"media_id":"999"}}]}]}`
+
+func TestHandleRaw(t *testing.T) {
+	t.Parallel()
+	scenarios := []hookScenario{
+		{
+			name:   "unsupported object",
+			method: http.MethodPost,
+			body:   strings.NewReader(unsupportedObjectPayload),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("instagram")
+						return nil
+					}}),
+					handler.Options.RawHandler(rawHandler{func(ctx context.Context, raw json.RawMessage) error {
+						scenario.trigger(string(raw))
+						return nil
+					}}),
+				}
+			},
+			expected: handler.Event{
+				Object: "unsupported",
+				Entry: []handler.Entry{{
+					Id:   "123",
+					Time: 1569262486134,
+					Changes: []handler.Change{{
+						Field: "mentions",
+						Raw:   json.RawMessage(`{"media_id":"999"}`),
+					}},
+				}},
+				Raw: json.RawMessage(unsupportedObjectPayload),
+			},
+			expectedHandlers: map[string]int{
+				`{"media_id":"999"}`: 1,
+			},
+		},
+		{
+			name:   "instagram messages field",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"messages","value":{"message":{"mid":"890"}}}]}]}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.WhatsAppHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("whatsapp")
+						return nil
+					}}),
+					handler.Options.RawChangeHandler(rawHandler{func(ctx context.Context, raw json.RawMessage) error {
+						scenario.trigger(string(raw))
+						return nil
+					}}),
+				}
+			},
+			expected: handler.Event{
+				Object: handler.Instagram,
+				Entry: []handler.Entry{{
+					Id:   "123",
+					Time: 1569262486134,
+					Changes: []handler.Change{{
+						Field: "messages",
+						Raw:   json.RawMessage(`{"message":{"mid":"890"}}`),
+					}},
+				}},
+			},
+			expectedHandlers: map[string]int{
+				`{"message":{"mid":"890"}}`: 1,
+			},
+		},
+		{
+			name:   "unsupported object messaging without raw messaging handler",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{"object":"unsupported","entry":[{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"890"}}]}]}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("instagram")
+						return nil
+					}}),
+					handler.Options.RawChangeHandler(rawHandler{func(ctx context.Context, raw json.RawMessage) error {
+						scenario.trigger(string(raw))
+						return nil
+					}}),
+				}
+			},
+			expectErr: gometawebhooks.ErrObjectNotSupported,
+		},
+		{
+			name:   "unsupported field",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"mentions","value":{"media_id":"999"}},{"field":"unsupported","value":{"id":"1"}}]}]}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("instagram")
+						return nil
+					}}),
+					handler.Options.RawChangeHandler(rawHandler{func(ctx context.Context, raw json.RawMessage) error {
+						scenario.trigger(string(raw))
+						return nil
+					}}),
+				}
+			},
+			expected: handler.Event{
+				Object: handler.Instagram,
+				Entry: []handler.Entry{{
+					Id:   "123",
+					Time: 1569262486134,
+					Changes: []handler.Change{{
+						Field: "mentions",
+						Value: handler.Mention{
+							MediaID: "999",
+						},
+					}, {
+						Field: "unsupported",
+						Raw:   json.RawMessage(`{"id":"1"}`),
+					}},
+				}},
+			},
+			expectedHandlers: map[string]int{
+				"instagram":  1,
+				`{"id":"1"}`: 1,
+			},
+		},
+		{
+			name:   "unsupported messaging type",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message_edit":{"mid":"890"}}]}]}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.RawMessagingHandler(rawHandler{func(ctx context.Context, raw json.RawMessage) error {
+						scenario.trigger(string(raw))
+						return nil
+					}}),
+				}
+			},
+			expected: handler.Event{
+				Object: handler.Instagram,
+				Entry: []handler.Entry{{
+					Id:   "123",
+					Time: 1569262486134,
+					Messaging: []handler.Messaging{{
+						Raw: json.RawMessage(`{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message_edit":{"mid":"890"}}`),
+					}},
+				}},
+			},
+			expectedHandlers: map[string]int{
+				`{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message_edit":{"mid":"890"}}`: 1,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.test(t, func(t *testing.T) {
+			hooks, req := scenario.setup(t)
+
+			ctx, cancel := context.WithTimeout(context.Background(), scenario.timeout)
+			defer cancel()
+
+			result, payload, err := hooks.HandleRequest(ctx, req)
+
+			scenario.assert(t, result, payload, err)
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	gometawebhooks "github.com/pnmcosta/go-meta-webhooks"
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+type messageEdit struct {
+	handler.MessagingHeader
+
+	MessageEdit struct {
+		Id      string `json:"mid"`
+		Text    string `json:"text"`
+		NumEdit int    `json:"num_edit"`
+	} `json:"message_edit"`
+}
+
+type customField struct {
+	Id    string `json:"id"`
+	Count int    `json:"count"`
+}
+
+func TestHandleRegistry(t *testing.T) {
+	t.Parallel()
+	scenarios := []hookScenario{
+		{
+			name:   "unregistered messaging type",
+			method: http.MethodPost,
+			body: strings.NewReader(`{
+				"object": "instagram",
+				"entry": [{
+					"id": "123",
+					"time": 1569262486134,
+					"messaging": [{
+						"sender": {
+							"id": "567"
+						},
+						"recipient": {
+							"id": "123"
+						},
+						"timestamp": 1569262485349,
+						"message_edit": {
+							"mid": "MESSAGE-ID",
+							"text": "edited",
+							"num_edit": 1
+						}
+					}]
+				}]
+			}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+				}
+			},
+			expectErr: gometawebhooks.ErrMessagingTypeNotImplemented,
+		},
+		{
+			name:   "registered messaging type",
+			method: http.MethodPost,
+			body: strings.NewReader(`{
+				"object": "instagram",
+				"entry": [{
+					"id": "123",
+					"time": 1569262486134,
+					"messaging": [{
+						"sender": {
+							"id": "567"
+						},
+						"recipient": {
+							"id": "123"
+						},
+						"timestamp": 1569262485349,
+						"message_edit": {
+							"mid": "MESSAGE-ID",
+							"text": "edited",
+							"num_edit": 1
+						}
+					},{
+						"sender": {
+							"id": "567"
+						},
+						"recipient": {
+							"id": "123"
+						},
+						"timestamp": 1569262485349,
+						"message": {
+							"mid": "MESSAGE-ID",
+							"text": "original"
+						}
+					}]
+				}]
+			}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.MessagingType("message_edit", gometawebhooks.MessagingTypeFunc(func(ctx context.Context, object handler.Object, entry handler.Entry, edit messageEdit) error {
+						scenario.trigger(edit.Sender.Id + ":" + edit.MessageEdit.Text)
+						return nil
+					})),
+					handler.Options.InstagramMessageHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("message")
+						return nil
+					}}),
+				}
+			},
+			expectedHandlers: map[string]int{
+				"567:edited": 1,
+				"message":    1,
+			},
+		},
+		{
+			name:   "unregistered object change field",
+			method: http.MethodPost,
+			body: strings.NewReader(`{
+				"object": "page",
+				"entry": [{
+					"id": "123",
+					"time": 1569262486134,
+					"changes": [{
+						"field": "custom",
+						"value": {
+							"id": "999",
+							"count": 2
+						}
+					}]
+				}]
+			}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.ChangeField(handler.Instagram, "custom", gometawebhooks.ChangeFieldFunc(func(ctx context.Context, object handler.Object, entry handler.Entry, value customField) error {
+						scenario.trigger("custom")
+						return nil
+					})),
+				}
+			},
+			expectErr: gometawebhooks.ErrChangesFieldNotImplemented,
+		},
+		{
+			name:   "registered change field",
+			method: http.MethodPost,
+			body: strings.NewReader(`{
+				"object": "page",
+				"entry": [{
+					"id": "123",
+					"time": 1569262486134,
+					"changes": [{
+						"field": "custom",
+						"value": {
+							"id": "999",
+							"count": 2
+						}
+					}]
+				}]
+			}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.ChangeField(handler.Page, "custom", gometawebhooks.ChangeFieldFunc(func(ctx context.Context, object handler.Object, entry handler.Entry, value customField) error {
+						scenario.trigger(value.Id)
+						return nil
+					})),
+				}
+			},
+			expectedHandlers: map[string]int{
+				"999": 1,
+			},
+		},
+		{
+			name:   "registered change field overrides built-in",
+			method: http.MethodPost,
+			body: strings.NewReader(`{
+				"object": "instagram",
+				"entry": [{
+					"id": "123",
+					"time": 1569262486134,
+					"changes": [{
+						"field": "mentions",
+						"value": {
+							"media_id": "999"
+						}
+					}]
+				}]
+			}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.InstagramMentionHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("mention")
+						return nil
+					}}),
+					handler.Options.ChangeField(handler.Instagram, "mentions", gometawebhooks.ChangeFieldFunc(func(ctx context.Context, object handler.Object, entry handler.Entry, value map[string]string) error {
+						scenario.trigger(value["media_id"])
+						return nil
+					})),
+				}
+			},
+			expectedHandlers: map[string]int{
+				"999": 1,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.test(t, func(t *testing.T) {
+			hooks, req := scenario.setup(t)
+
+			ctx, cancel := context.WithTimeout(context.Background(), scenario.timeout)
+			defer cancel()
+
+			_, payload, err := hooks.HandleRequest(ctx, req)
+
+			scenario.assert(t, nil, payload, err)
+		})
+	}
+}
+
+func TestRegistryOptions(t *testing.T) {
+	t.Parallel()
+
+	field := gometawebhooks.ChangeFieldFunc(func(ctx context.Context, object handler.Object, entry handler.Entry, value customField) error {
+		return nil
+	})
+	messaging := gometawebhooks.MessagingTypeFunc(func(ctx context.Context, object handler.Object, entry handler.Entry, edit messageEdit) error {
+		return nil
+	})
+
+	scenarios := []struct {
+		name      string
+		option    handler.Option
+		expectErr error
+	}{
+		{"change field without object", handler.Options.ChangeField("", "custom", field), gometawebhooks.ErrRegistryKeyRequired},
+		{"change field without field", handler.Options.ChangeField(handler.Instagram, "", field), gometawebhooks.ErrRegistryKeyRequired},
+		{"change field without handler", handler.Options.ChangeField(handler.Instagram, "custom", nil), gometawebhooks.ErrRegistryHandlerRequired},
+		{"messaging type without key", handler.Options.MessagingType("", messaging), gometawebhooks.ErrRegistryKeyRequired},
+		{"messaging type without handler", handler.Options.MessagingType("message_edit", nil), gometawebhooks.ErrRegistryHandlerRequired},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			_, err := handler.New(scenario.option)
+			if !errors.Is(err, scenario.expectErr) || !errors.Is(err, gometawebhooks.ErrApplyingOption) {
+				t.Errorf("Expected error %v, but got %v", scenario.expectErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	gometawebhooks "github.com/pnmcosta/go-meta-webhooks"
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+func TestReplayWindow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	payload := func(entryTime, timestamp int64) string {
+		return fmt.Sprintf(`{"object":"instagram","entry":[{"id":"123","time":%d,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":%d,"message":{"mid":"890"}}]}]}`, entryTime, timestamp)
+	}
+	expected := func(entryTime, timestamp int64, stale bool) handler.Event {
+		message := handler.MessagingMessage{
+			Message: handler.Message{Id: "890"},
+		}
+		message.Sender.Id = "567"
+		message.Recipient.Id = "123"
+		message.Timestamp = timestamp
+
+		return handler.Event{
+			Object: handler.Instagram,
+			Entry: []handler.Entry{{
+				Id:        "123",
+				Time:      entryTime,
+				Messaging: []handler.Messaging{{Type: message}},
+			}},
+			Stale: stale,
+		}
+	}
+
+	fresh := now.UnixMilli()
+	old := now.Add(-2 * time.Hour).UnixMilli()
+	future := now.Add(time.Hour).UnixMilli()
+
+	scenarios := []hookScenario{
+		{
+			name:   "fresh event",
+			method: http.MethodPost,
+			body:   strings.NewReader(payload(fresh, fresh)),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.ReplayWindow(time.Hour, time.Minute),
+				}
+			},
+			expected: expected(fresh, fresh, false),
+		},
+		{
+			name:   "old entry",
+			method: http.MethodPost,
+			body:   strings.NewReader(payload(old, fresh)),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.ReplayWindow(time.Hour, time.Minute),
+				}
+			},
+			expectErr: gometawebhooks.ErrStaleEvent,
+		},
+		{
+			name:   "future messaging",
+			method: http.MethodPost,
+			body:   strings.NewReader(payload(fresh, future)),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.ReplayWindow(time.Hour, time.Minute),
+				}
+			},
+			expectErr: gometawebhooks.ErrStaleEvent,
+		},
+		{
+			name:   "seconds timestamp",
+			method: http.MethodPost,
+			body:   strings.NewReader(payload(now.Add(-2*time.Hour).Unix(), fresh)),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.ReplayWindow(time.Hour, time.Minute),
+				}
+			},
+			expectErr: gometawebhooks.ErrStaleEvent,
+		},
+		{
+			name:   "changes entry in seconds",
+			method: http.MethodPost,
+			body:   strings.NewReader(fmt.Sprintf(`{"object":"instagram","entry":[{"id":"123","time":%d,"changes":[{"field":"mentions","value":{"media_id":"999"}}]}]}`, now.Unix())),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.ReplayWindow(time.Hour, time.Minute),
+				}
+			},
+			expected: handler.Event{
+				Object: handler.Instagram,
+				Entry: []handler.Entry{{
+					Id:   "123",
+					Time: now.Unix(),
+					Changes: []handler.Change{{
+						Field: "mentions",
+						Value: handler.Mention{MediaID: "999"},
+					}},
+				}},
+			},
+		},
+		{
+			name:   "flags stale event",
+			method: http.MethodPost,
+			body:   strings.NewReader(payload(old, old)),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.ReplayWindow(time.Hour, time.Minute),
+					handler.Options.FlagStaleEvents(true),
+				}
+			},
+			expected: expected(old, old, true),
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.test(t, func(t *testing.T) {
+			hooks, req := scenario.setup(t)
+
+			result, payload, err := hooks.(handler.RequestParser).ParseRequest(req)
+
+			scenario.assert(t, result, payload, err)
+		})
+	}
+}
+
+func TestReplayCache(t *testing.T) {
+	t.Parallel()
+
+	const payload = `{"object":"instagram", "entry":[]}`
+
+	hooks, err := handler.New(
+		handler.Options.CompileSchema(),
+		handler.Options.Secret("very_secret"),
+		handler.Options.ReplayCache(gometawebhooks.NewMemoryDedupStore(time.Hour, 0)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := http.Header{}
+	header.Set("X-Hub-Signature-256", genHmac("very_secret", payload))
+
+	// verifying doesn't record the signature
+	for i := 0; i < 2; i++ {
+		if _, err := hooks.VerifyPayloadSecret(context.Background(), []byte(payload), header); err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+	}
+
+	if err := hooks.RecordSignature(context.Background(), header); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	err = hooks.RecordSignature(context.Background(), header)
+	if !errors.Is(err, gometawebhooks.ErrStaleEvent) || !errors.Is(err, gometawebhooks.ErrReplayedSignature) {
+		t.Errorf("Expected error %v, but got %v", gometawebhooks.ErrReplayedSignature, err)
+	}
+}
+
+func TestReplayCacheRedelivery(t *testing.T) {
+	t.Parallel()
+
+	const payload = `{"object":"instagram","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"mentions","value":{"media_id":"999"}}]}]}`
+
+	calls := 0
+	hooks, err := handler.New(
+		handler.Options.Secret("very_secret"),
+		handler.Options.ReplayCache(gometawebhooks.NewMemoryDedupStore(time.Hour, 0)),
+		handler.Options.InstagramMentionHandler(testHandler{func(ctx context.Context) error {
+			calls++
+			if calls == 1 {
+				return errors.New("failed")
+			}
+			return nil
+		}}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the failed delivery is redelivered, the handled one is rejected
+	for i, expected := range []int{http.StatusInternalServerError, http.StatusOK, http.StatusBadRequest} {
+		req := newRequest(http.MethodPost, payload)
+		req.Header.Set("X-Hub-Signature-256", genHmac("very_secret", payload))
+
+		_, _, err := hooks.HandleRequest(context.Background(), req)
+		if status := handler.StatusCode(err); status != expected {
+			t.Errorf("Delivery %d expected status %d, but got %d: %v", i, expected, status, err)
+		}
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, but got %d", calls)
+	}
+}
+
+func TestIsStale(t *testing.T) {
+	t.Parallel()
+
+	old := time.Now().Add(-2 * time.Hour).UnixMilli()
+	payload := fmt.Sprintf(`{"object":"instagram","entry":[{"id":"123","time":%d,"changes":[{"field":"mentions","value":{"media_id":"999"}}]}]}`, old)
+
+	var stale bool
+	hooks, err := handler.New(
+		handler.Options.ReplayWindow(time.Hour, time.Minute),
+		handler.Options.FlagStaleEvents(true),
+		handler.Options.InstagramMentionHandler(testHandler{func(ctx context.Context) error {
+			stale = gometawebhooks.IsStale(ctx)
+			return nil
+		}}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := hooks.HandleRequest(context.Background(), newRequest(http.MethodPost, payload)); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stale {
+		t.Errorf("Expected stale event")
+	}
+}
@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	gometawebhooks "github.com/pnmcosta/go-meta-webhooks"
+)
+
+var (
+	ErrNoRoute = errors.New("no route for request")
+)
+
+// RouterOption is a configuration option for the router
+type RouterOption func(*router) error
+
+// RouterOptions is a namespace var for router configuration options
+var RouterOptions = RouterHandlerOptions{}
+
+// RouterHandlerOptions is a namespace for router configuration option methods
+type RouterHandlerOptions struct{}
+
+// Registers the webhooks of a Meta App under key, served by an HTTP handler created with opts
+func (RouterHandlerOptions) Route(key string, hooks DefaultHandler, opts ...HTTPOption) RouterOption {
+	return func(rt *router) error {
+		h, err := NewHTTPHandler(hooks, opts...)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := rt.routes[key]; !ok {
+			rt.keys = append(rt.keys, key)
+		}
+		rt.routes[key] = h
+		return nil
+	}
+}
+
+// Routes POST requests with entries of any of the page or Instagram account ids to the route key
+func (RouterHandlerOptions) EntryIds(key string, ids ...string) RouterOption {
+	return func(rt *router) error {
+		for _, id := range ids {
+			rt.entries[id] = key
+		}
+		return nil
+	}
+}
+
+// Selects the route key from the request path value name, e.g. {app} of "POST /webhooks/{app}"
+func (RouterHandlerOptions) PathValue(name string) RouterOption {
+	return func(rt *router) error {
+		rt.pathValue = name
+		return nil
+	}
+}
+
+// Selects the route key from the request header name
+func (RouterHandlerOptions) Header(name string) RouterOption {
+	return func(rt *router) error {
+		rt.header = name
+		return nil
+	}
+}
+
+// Limits the size of payloads read to select routes by entry ids, see Options.MaxBodyBytes, defaults to the
+// largest limit of the routed webhooks, unbounded when any of them is
+func (RouterHandlerOptions) MaxBodyBytes(n int64) RouterOption {
+	return func(rt *router) error {
+		rt.maxBodyBytes = n
+		rt.maxBodyBytesSet = true
+		return nil
+	}
+}
+
+// Sets the webhooks serving requests which do not match any route
+func (RouterHandlerOptions) Default(hooks DefaultHandler, opts ...HTTPOption) RouterOption {
+	return func(rt *router) error {
+		h, err := NewHTTPHandler(hooks, opts...)
+		if err != nil {
+			return err
+		}
+
+		rt.fallback = h
+		return nil
+	}
+}
+
+// Sets a custom error response writer for requests which do not match any route, defaults to DefaultErrorHandler
+func (RouterHandlerOptions) ErrorHandler(fn ErrorHandlerFunc) RouterOption {
+	return func(rt *router) error {
+		rt.errorHandler = fn
+		return nil
+	}
+}
+
+var _ http.Handler = (*router)(nil)
+
+type router struct {
+	keys    []string
+	routes  map[string]*httpHandler
+	entries map[string]string
+
+	maxBodyBytes    int64
+	maxBodyBytesSet bool
+
+	pathValue string
+	header    string
+
+	fallback     *httpHandler
+	errorHandler ErrorHandlerFunc
+}
+
+// Creates and returns an http.Handler serving many Meta Apps from one endpoint, each request is routed to the webhooks
+// selected by path value, header or entry ids, in that order, falling back to the default webhooks.
+// GET requests which do not select a route are verified against the token of each route.
+func NewRouter(opts ...RouterOption) (*router, error) {
+	rt := &router{
+		routes:       make(map[string]*httpHandler),
+		entries:      make(map[string]string),
+		errorHandler: DefaultErrorHandler,
+	}
+
+	for _, opt := range opts {
+		if err := opt(rt); err != nil {
+			return nil, wrapErr(err, gometawebhooks.ErrApplyingOption)
+		}
+	}
+
+	for id, key := range rt.entries {
+		if _, ok := rt.routes[key]; !ok {
+			return nil, fmt.Errorf("entry '%s' of undefined route '%s': %w", id, key, ErrNoRoute)
+		}
+	}
+
+	if !rt.maxBodyBytesSet {
+		rt.maxBodyBytes = rt.routesMaxBodyBytes()
+	}
+
+	return rt, nil
+}
+
+// Returns the largest MaxBodyBytes of the routed webhooks, zero when any of them reads unbounded
+func (rt router) routesMaxBodyBytes() int64 {
+	handlers := make([]*httpHandler, 0, len(rt.routes)+1)
+	for _, h := range rt.routes {
+		handlers = append(handlers, h)
+	}
+	if rt.fallback != nil {
+		handlers = append(handlers, rt.fallback)
+	}
+
+	var largest int64
+	for _, h := range handlers {
+		limited, ok := h.hooks.(interface{ MaxBodyBytes() int64 })
+		if !ok || limited.MaxBodyBytes() <= 0 {
+			return 0
+		}
+		largest = max(largest, limited.MaxBodyBytes())
+	}
+	return largest
+}
+
+func (rt router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h, err := rt.route(r)
+	if err != nil {
+		rt.errorHandler(w, r, err)
+		return
+	}
+
+	if h == nil && r.Method == http.MethodGet {
+		h = rt.verified(r)
+	}
+
+	if h == nil {
+		h = rt.fallback
+	}
+
+	if h == nil {
+		rt.errorHandler(w, r, ErrNoRoute)
+		return
+	}
+
+	h.ServeHTTP(w, r)
+}
+
+// Returns the handler of the route selected by the request, nil when none matches
+func (rt router) route(r *http.Request) (*httpHandler, error) {
+	if rt.pathValue != "" {
+		if h, ok := rt.routes[r.PathValue(rt.pathValue)]; ok {
+			return h, nil
+		}
+	}
+
+	if rt.header != "" {
+		if h, ok := rt.routes[r.Header.Get(rt.header)]; ok {
+			return h, nil
+		}
+	}
+
+	if len(rt.entries) > 0 && r.Method == http.MethodPost {
+		return rt.entry(r)
+	}
+
+	return nil, nil
+}
+
+// Returns the handler of the route of the first known entry id, restoring the request body to be verified by it
+func (rt router) entry(r *http.Request) (*httpHandler, error) {
+	body, err := gometawebhooks.ReadPayload(r.Body, "", rt.maxBodyBytes)
+	_ = r.Body.Close()
+	if err != nil {
+		return nil, wrapErr(err, ErrReadBodyPayload)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	// left to the fallback to fail verifying, validating or parsing
+	payload, err := gometawebhooks.ReadPayload(bytes.NewReader(body), r.Header.Get("Content-Encoding"), rt.maxBodyBytes)
+	if err != nil {
+		return nil, nil
+	}
+
+	var event struct {
+		Entry []struct {
+			Id string `json:"id"`
+		} `json:"entry"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, nil
+	}
+
+	for _, entry := range event.Entry {
+		if key, ok := rt.entries[entry.Id]; ok {
+			return rt.routes[key], nil
+		}
+	}
+	return nil, nil
+}
+
+// Returns the handler of the first route which verifies the subscription request, nil when none does
+func (rt router) verified(r *http.Request) *httpHandler {
+	for _, key := range rt.keys {
+		h := rt.routes[key]
+		if _, err := h.hooks.HandleVerify(r); err == nil {
+			return h
+		}
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+func TestRouter(t *testing.T) {
+	t.Parallel()
+
+	const payloadA = `{"object":"instagram","entry":[{"id":"111","time":1569262486134,"changes":[{"field":"mentions","value":{"media_id":"999"}}]}]}`
+	const payloadB = `{"object":"instagram","entry":[{"id":"222","time":1569262486134,"changes":[{"field":"mentions","value":{"media_id":"999"}}]}]}`
+
+	newHooks := func(t *testing.T, token, secret string) handler.DefaultHandler {
+		hooks, err := handler.New(
+			handler.Options.CompileSchema(),
+			handler.Options.Token(token),
+			handler.Options.Secret(secret),
+			handler.Options.InstagramMentionHandler(testHandler{func(ctx context.Context) error {
+				return nil
+			}}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hooks
+	}
+
+	scenarios := []struct {
+		name     string
+		options  func(t *testing.T) []handler.RouterOption
+		method   string
+		url      string
+		headers  map[string]string
+		body     string
+		expected int
+	}{
+		{
+			name: "path value",
+			options: func(t *testing.T) []handler.RouterOption {
+				return []handler.RouterOption{
+					handler.RouterOptions.PathValue("app"),
+					handler.RouterOptions.Route("a", newHooks(t, "token_a", "secret_a")),
+					handler.RouterOptions.Route("b", newHooks(t, "token_b", "secret_b")),
+				}
+			},
+			method:   http.MethodPost,
+			url:      "/webhooks/b",
+			headers:  map[string]string{"X-Hub-Signature-256": genHmac("secret_b", payloadA)},
+			body:     payloadA,
+			expected: http.StatusOK,
+		},
+		{
+			name: "path value wrong secret",
+			options: func(t *testing.T) []handler.RouterOption {
+				return []handler.RouterOption{
+					handler.RouterOptions.PathValue("app"),
+					handler.RouterOptions.Route("a", newHooks(t, "token_a", "secret_a")),
+					handler.RouterOptions.Route("b", newHooks(t, "token_b", "secret_b")),
+				}
+			},
+			method:   http.MethodPost,
+			url:      "/webhooks/a",
+			headers:  map[string]string{"X-Hub-Signature-256": genHmac("secret_b", payloadA)},
+			body:     payloadA,
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "header",
+			options: func(t *testing.T) []handler.RouterOption {
+				return []handler.RouterOption{
+					handler.RouterOptions.Header("X-App"),
+					handler.RouterOptions.Route("a", newHooks(t, "token_a", "secret_a")),
+					handler.RouterOptions.Route("b", newHooks(t, "token_b", "secret_b")),
+				}
+			},
+			method: http.MethodPost,
+			url:    "/webhooks/meta",
+			headers: map[string]string{
+				"X-App":               "a",
+				"X-Hub-Signature-256": genHmac("secret_a", payloadA),
+			},
+			body:     payloadA,
+			expected: http.StatusOK,
+		},
+		{
+			name: "entry ids",
+			options: func(t *testing.T) []handler.RouterOption {
+				return []handler.RouterOption{
+					handler.RouterOptions.Route("a", newHooks(t, "token_a", "secret_a")),
+					handler.RouterOptions.Route("b", newHooks(t, "token_b", "secret_b")),
+					handler.RouterOptions.EntryIds("a", "111"),
+					handler.RouterOptions.EntryIds("b", "222"),
+				}
+			},
+			method:   http.MethodPost,
+			url:      "/webhooks/meta",
+			headers:  map[string]string{"X-Hub-Signature-256": genHmac("secret_b", payloadB)},
+			body:     payloadB,
+			expected: http.StatusOK,
+		},
+		{
+			name: "entry ids wrong secret",
+			options: func(t *testing.T) []handler.RouterOption {
+				return []handler.RouterOption{
+					handler.RouterOptions.Route("a", newHooks(t, "token_a", "secret_a")),
+					handler.RouterOptions.Route("b", newHooks(t, "token_b", "secret_b")),
+					handler.RouterOptions.EntryIds("a", "111"),
+					handler.RouterOptions.EntryIds("b", "222"),
+				}
+			},
+			method:   http.MethodPost,
+			url:      "/webhooks/meta",
+			headers:  map[string]string{"X-Hub-Signature-256": genHmac("secret_b", payloadA)},
+			body:     payloadA,
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "default",
+			options: func(t *testing.T) []handler.RouterOption {
+				return []handler.RouterOption{
+					handler.RouterOptions.Route("a", newHooks(t, "token_a", "secret_a")),
+					handler.RouterOptions.EntryIds("a", "111"),
+					handler.RouterOptions.Default(newHooks(t, "token_default", "secret_default")),
+				}
+			},
+			method:   http.MethodPost,
+			url:      "/webhooks/meta",
+			headers:  map[string]string{"X-Hub-Signature-256": genHmac("secret_default", payloadB)},
+			body:     payloadB,
+			expected: http.StatusOK,
+		},
+		{
+			name: "no route",
+			options: func(t *testing.T) []handler.RouterOption {
+				return []handler.RouterOption{
+					handler.RouterOptions.Route("a", newHooks(t, "token_a", "secret_a")),
+					handler.RouterOptions.EntryIds("a", "111"),
+				}
+			},
+			method:   http.MethodPost,
+			url:      "/webhooks/meta",
+			headers:  map[string]string{"X-Hub-Signature-256": genHmac("secret_a", payloadB)},
+			body:     payloadB,
+			expected: http.StatusNotFound,
+		},
+		{
+			name: "verifies route token",
+			options: func(t *testing.T) []handler.RouterOption {
+				return []handler.RouterOption{
+					handler.RouterOptions.Route("a", newHooks(t, "token_a", "secret_a")),
+					handler.RouterOptions.Route("b", newHooks(t, "token_b", "secret_b")),
+				}
+			},
+			method:   http.MethodGet,
+			url:      "/webhooks/meta?hub.mode=subscribe&hub.verify_token=token_b&hub.challenge=challenge_response",
+			expected: http.StatusOK,
+		},
+		{
+			name: "invalid route token",
+			options: func(t *testing.T) []handler.RouterOption {
+				return []handler.RouterOption{
+					handler.RouterOptions.Route("a", newHooks(t, "token_a", "secret_a")),
+					handler.RouterOptions.Default(newHooks(t, "token_default", "secret_default")),
+				}
+			},
+			method:   http.MethodGet,
+			url:      "/webhooks/meta?hub.mode=subscribe&hub.verify_token=token_b&hub.challenge=challenge_response",
+			expected: http.StatusForbidden,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			rt, err := handler.NewRouter(scenario.options(t)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			mux := http.NewServeMux()
+			mux.Handle("/webhooks/{app}", rt)
+
+			req := httptest.NewRequest(scenario.method, scenario.url, strings.NewReader(scenario.body))
+			for k, v := range scenario.headers {
+				req.Header.Set(k, v)
+			}
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != scenario.expected {
+				t.Errorf("Expected status %d, but got %d", scenario.expected, rec.Code)
+			}
+
+			if scenario.method == http.MethodGet && rec.Code == http.StatusOK && rec.Body.String() != "challenge_response" {
+				t.Errorf("Expected challenge_response, but got %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRouterUndefinedEntryRoute(t *testing.T) {
+	t.Parallel()
+
+	_, err := handler.NewRouter(handler.RouterOptions.EntryIds("a", "111"))
+	if !errors.Is(err, handler.ErrNoRoute) {
+		t.Errorf("Expected error %v, but got %v", handler.ErrNoRoute, err)
+	}
+}
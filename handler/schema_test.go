@@ -0,0 +1,158 @@
+package handler_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	gometawebhooks "github.com/pnmcosta/go-meta-webhooks"
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+// Requires every entry to have messaging
+const strictSchema = `{"type":"object","required":["object","entry"],"properties":{"entry":{"type":"array","items":{"type":"object","required":["id","messaging"]}}}}`
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+
+	const (
+		messagingPayload = `{"object":"instagram","entry":[{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"890"}}]}]}`
+		changesPayload   = `{"object":"instagram","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"mentions","value":{"media_id":"999"}}]}]}`
+		locationPayload  = `{"object":"%s","entry":[{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"890","attachments":[{"type":"location","payload":{"coordinates":{"lat":38.72,"long":-9.14}}}]}}]}]}`
+		customPayload    = `{"object":"custom","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"anything","value":{"id":"1"}}]}]}`
+	)
+
+	scenarios := []struct {
+		name       string
+		options    []handler.Option
+		payload    string
+		expectErr  error
+		handlerNew bool
+	}{
+		{
+			name:       "embedded schema by default",
+			payload:    changesPayload,
+			handlerNew: true,
+		},
+		{
+			name:    "missing schema",
+			payload: changesPayload,
+			// @note gometawebhooks.New doesn't compile the embedded schema
+			expectErr: gometawebhooks.ErrMissingSchema,
+		},
+		{
+			name:       "page attachments",
+			payload:    fmt.Sprintf(locationPayload, "page"),
+			handlerNew: true,
+		},
+		{
+			name:       "instagram attachments rejects",
+			payload:    fmt.Sprintf(locationPayload, "instagram"),
+			expectErr:  gometawebhooks.ErrInvalidPayload,
+			handlerNew: true,
+		},
+		{
+			name:       "custom schema",
+			options:    []handler.Option{handler.Options.Schema(strings.NewReader(strictSchema))},
+			payload:    messagingPayload,
+			handlerNew: true,
+		},
+		{
+			name:       "custom schema rejects",
+			options:    []handler.Option{handler.Options.Schema(strings.NewReader(strictSchema))},
+			payload:    changesPayload,
+			expectErr:  gometawebhooks.ErrInvalidPayload,
+			handlerNew: true,
+		},
+		{
+			name: "schema fs rejects",
+			options: []handler.Option{handler.Options.SchemaFS(fstest.MapFS{
+				"schemas/strict.json": {Data: []byte(strictSchema)},
+			}, "schemas/strict.json")},
+			payload:    changesPayload,
+			expectErr:  gometawebhooks.ErrInvalidPayload,
+			handlerNew: true,
+		},
+		{
+			name: "object schema",
+			options: []handler.Option{
+				handler.Options.ObjectSchema("custom", strings.NewReader(`{"type":"object","required":["entry"]}`)),
+				handler.Options.RawChangeHandler(rawHandler{}),
+			},
+			payload: customPayload,
+		},
+		{
+			name: "object schema rejects",
+			options: []handler.Option{
+				handler.Options.ObjectSchema("custom", strings.NewReader(strictSchema)),
+				handler.Options.RawChangeHandler(rawHandler{}),
+			},
+			payload:    customPayload,
+			expectErr:  gometawebhooks.ErrInvalidPayload,
+			handlerNew: true,
+		},
+		{
+			name: "object schema only",
+			options: []handler.Option{
+				handler.Options.ObjectSchema("custom", strings.NewReader(`{"type":"object"}`)),
+			},
+			payload:   changesPayload,
+			expectErr: gometawebhooks.ErrMissingSchema,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			create := func(opts ...handler.Option) (interface{ ValidatePayload([]byte) error }, error) {
+				return gometawebhooks.New(opts...)
+			}
+			if scenario.handlerNew {
+				create = func(opts ...handler.Option) (interface{ ValidatePayload([]byte) error }, error) {
+					return handler.New(opts...)
+				}
+			}
+
+			hooks, err := create(scenario.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = hooks.ValidatePayload([]byte(scenario.payload))
+			if scenario.expectErr != nil {
+				if !errors.Is(err, scenario.expectErr) {
+					t.Errorf("Expected error %v, but got %v", scenario.expectErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestSchemaCompile(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name   string
+		option handler.Option
+	}{
+		{"invalid schema", handler.Options.Schema(strings.NewReader(`{"type":1}`))},
+		{"malformed schema", handler.Options.Schema(strings.NewReader(`{`))},
+		{"missing schema file", handler.Options.SchemaFS(fstest.MapFS{}, "schema.json")},
+		{"invalid object schema", handler.Options.ObjectSchema("custom", strings.NewReader(`{"type":1}`))},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			_, err := handler.New(scenario.option)
+			if !errors.Is(err, gometawebhooks.ErrSchemaCompile) {
+				t.Errorf("Expected error %v, but got %v", gometawebhooks.ErrSchemaCompile, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package handler_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	gometawebhooks "github.com/pnmcosta/go-meta-webhooks"
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+var errSecretStore = errors.New("secret store unavailable")
+
+func TestSignature(t *testing.T) {
+	t.Parallel()
+
+	payload := `{"object":"instagram", "entry":[]}`
+	expected := handler.Event{
+		Object: handler.Instagram,
+		Entry:  []handler.Entry{},
+	}
+
+	scenarios := []hookScenario{
+		{
+			name:   "custom header name case-insensitive",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"X-Custom-Signature": genHmac("very_secret", payload),
+			},
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.Secret("very_secret"),
+					handler.Options.CustomHeaderSigName("x-custom-signature"),
+				}
+			},
+			body:     strings.NewReader(payload),
+			expected: expected,
+		},
+		{
+			name:   "missing prefix",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"X-Hub-Signature-256": strings.TrimPrefix(genHmac("very_secret", payload), "sha256="),
+			},
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.Secret("very_secret"),
+				}
+			},
+			body:      strings.NewReader(payload),
+			expectErr: gometawebhooks.ErrMalformedSignature,
+		},
+		{
+			name:   "invalid hex",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"X-Hub-Signature-256": "sha256=zz",
+			},
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.Secret("very_secret"),
+				}
+			},
+			body:      strings.NewReader(payload),
+			expectErr: gometawebhooks.ErrMalformedSignature,
+		},
+		{
+			name:   "mismatch",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"X-Hub-Signature-256": genHmac("other_secret", payload),
+			},
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.Secret("very_secret"),
+				}
+			},
+			body:      strings.NewReader(payload),
+			expectErr: gometawebhooks.ErrSignatureMismatch,
+		},
+		{
+			name:   "rotated secrets",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"X-Hub-Signature-256": genHmac("old_secret", payload),
+			},
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.Secrets("new_secret", "old_secret"),
+				}
+			},
+			body:     strings.NewReader(payload),
+			expected: expected,
+		},
+		{
+			name:   "rotated secrets mismatch",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"X-Hub-Signature-256": genHmac("other_secret", payload),
+			},
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.Secrets("new_secret", "old_secret"),
+				}
+			},
+			body:      strings.NewReader(payload),
+			expectErr: gometawebhooks.ErrSignatureMismatch,
+		},
+		{
+			name:   "secret provider",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"X-Hub-Signature-256": genHmac("provided_secret", payload),
+			},
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.SecretProvider(handler.SecretProviderFunc(func(ctx context.Context) ([]string, error) {
+						return []string{"provided_secret"}, nil
+					})),
+				}
+			},
+			body:     strings.NewReader(payload),
+			expected: expected,
+		},
+		{
+			name:   "secret provider failure",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"X-Hub-Signature-256": genHmac("provided_secret", payload),
+			},
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.SecretProvider(handler.SecretProviderFunc(func(ctx context.Context) ([]string, error) {
+						return nil, errSecretStore
+					})),
+				}
+			},
+			body:      strings.NewReader(payload),
+			expectErr: errSecretStore,
+		},
+		{
+			name:   "secret provider without secrets",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"X-Hub-Signature-256": genHmac("provided_secret", payload),
+			},
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.SecretProvider(handler.SecretProviderFunc(func(ctx context.Context) ([]string, error) {
+						return nil, nil
+					})),
+				}
+			},
+			body:      strings.NewReader(payload),
+			expectErr: gometawebhooks.ErrSecretProvider,
+		},
+		{
+			name:   "legacy signature",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"X-Hub-Signature": genSha1Hmac("very_secret", payload),
+			},
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.Secret("very_secret"),
+					handler.Options.LegacySignature(true),
+				}
+			},
+			body:     strings.NewReader(payload),
+			expected: expected,
+		},
+		{
+			name:   "legacy signature mismatch",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"X-Hub-Signature": genSha1Hmac("other_secret", payload),
+			},
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.Secret("very_secret"),
+					handler.Options.LegacySignature(true),
+				}
+			},
+			body:      strings.NewReader(payload),
+			expectErr: gometawebhooks.ErrSignatureMismatch,
+		},
+		{
+			name:   "legacy signature disabled",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"X-Hub-Signature": genSha1Hmac("very_secret", payload),
+			},
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.Secret("very_secret"),
+				}
+			},
+			body:      strings.NewReader(payload),
+			expectErr: gometawebhooks.ErrMissingHubSignatureHeader,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.test(t, func(t *testing.T) {
+			hooks, req := scenario.setup(t)
+
+			ctx, cancel := context.WithTimeout(context.Background(), scenario.timeout)
+			defer cancel()
+
+			result, payload, err := hooks.HandleRequest(ctx, req)
+
+			scenario.assert(t, result, payload, err)
+		})
+	}
+}
+
+func TestVerifyPayloadHeaderNames(t *testing.T) {
+	t.Parallel()
+
+	hooks, err := handler.New(handler.Options.Secret("very_secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := `{"object":"instagram", "entry":[]}`
+	if err := hooks.VerifyPayload([]byte(payload), map[string]string{
+		"x-hub-signature-256": genHmac("very_secret", payload),
+	}); err != nil {
+		t.Errorf("Expected no error, but got: %v", err)
+	}
+}
+
+func TestVerifyPayloadSecret(t *testing.T) {
+	t.Parallel()
+
+	payload := `{"object":"instagram", "entry":[]}`
+
+	scenarios := []struct {
+		name      string
+		options   []handler.Option
+		secret    string
+		expected  int
+		expectErr error
+	}{
+		{
+			name:     "no secret",
+			expected: -1,
+		},
+		{
+			name:     "current secret",
+			options:  []handler.Option{handler.Options.Secrets("new_secret", "old_secret")},
+			secret:   "new_secret",
+			expected: 0,
+		},
+		{
+			name:     "previous secret",
+			options:  []handler.Option{handler.Options.Secrets("new_secret", "old_secret")},
+			secret:   "old_secret",
+			expected: 1,
+		},
+		{
+			name:      "unknown secret",
+			options:   []handler.Option{handler.Options.Secrets("new_secret", "old_secret")},
+			secret:    "other_secret",
+			expected:  -1,
+			expectErr: gometawebhooks.ErrHMACVerificationFailed,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			hooks, err := handler.New(scenario.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			header := http.Header{}
+			header.Set("X-Hub-Signature-256", genHmac(scenario.secret, payload))
+
+			index, err := hooks.VerifyPayloadSecret(context.Background(), []byte(payload), header)
+			if !errors.Is(err, scenario.expectErr) {
+				t.Errorf("Expected error %v, but got %v", scenario.expectErr, err)
+			}
+			if index != scenario.expected {
+				t.Errorf("Expected secret index %d, but got %d", scenario.expected, index)
+			}
+		})
+	}
+}
+
+func genSha1Hmac(secret, payload string) string {
+	mac := hmac.New(sha1.New, []byte(secret))
+	_, _ = mac.Write([]byte(payload))
+	return "sha1=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,94 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+func TestTimeAccessors(t *testing.T) {
+	t.Parallel()
+
+	millis := time.UnixMilli(1569262486134)
+	seconds := time.Unix(1669233778, 0)
+
+	scenarios := []struct {
+		name     string
+		result   time.Time
+		expected time.Time
+	}{
+		{"entry milliseconds", handler.Entry{Time: 1569262486134}.At(), millis},
+		{"entry seconds", handler.Entry{Time: 1669233778}.At(), seconds},
+		{"entry zero", handler.Entry{}.At(), time.Time{}},
+		{"messaging milliseconds", handler.MessagingHeader{Timestamp: 1569262486134}.At(), millis},
+		{"messaging zero", handler.MessagingHeader{}.At(), time.Time{}},
+		{"whatsapp message", handler.WhatsAppMessage{Timestamp: "1669233778"}.At(), seconds},
+		{"whatsapp status", handler.WhatsAppStatus{Timestamp: "1669233778"}.At(), seconds},
+		{"whatsapp invalid timestamp", handler.WhatsAppStatus{Timestamp: "invalid"}.At(), time.Time{}},
+		{"whatsapp conversation", handler.WhatsAppConversation{ExpirationTimestamp: "1669233778"}.ExpiresAt(), seconds},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			if !scenario.result.Equal(scenario.expected) {
+				t.Errorf("Expected %v, but got %v", scenario.expected, scenario.result)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name    string
+		payload string
+		options []handler.Option
+	}{
+		{
+			name:    "messaging",
+			payload: `{"object":"instagram","entry":[{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"890","text":"hello"}}]}]}`,
+		},
+		{
+			name:    "changes",
+			payload: `{"object":"instagram","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"mentions","value":{"media_id":"999","comment_id":"4444"}}]}]}`,
+		},
+		{
+			name:    "raw pieces",
+			payload: `{"object":"instagram","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"unknown","value":{"id":"1","time":1569262486}}]},{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"unknown":{"id":"1"}}]}]}`,
+			options: []handler.Option{
+				handler.Options.RawHandler(&rawHandler{}),
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			hooks, err := handler.New(scenario.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			event, err := hooks.ParsePayload([]byte(scenario.payload))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := json.Marshal(event)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var expected, result interface{}
+			_ = json.Unmarshal([]byte(scenario.payload), &expected)
+			_ = json.Unmarshal(b, &result)
+
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("Expected %s, but got %s", scenario.payload, b)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+func TestHandlerTimeout(t *testing.T) {
+	t.Parallel()
+	scenarios := []hookScenario{
+		{
+			name:   "exceeded ignoring context",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"story_insights","value":{"media_id":"999","exits":1,"replies":2,"reach":3,"taps_forward":4,"taps_back":5,"impressions":6}}]}]}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.HandlerTimeout(5 * time.Millisecond),
+					handler.Options.InstagramStoryInsightsHandler(testHandler{func(ctx context.Context) error {
+						time.Sleep(scenario.timeout)
+						return nil
+					}}),
+				}
+			},
+			expected: &handler.HandlerTimeoutError{
+				Object:  handler.Instagram,
+				EntryId: "123",
+				Kind:    "story_insights",
+				Timeout: 5 * time.Millisecond,
+			},
+			timeout: time.Second,
+		},
+		{
+			name:   "exceeded returning cause",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"messaging":[{"sender":{"id":"567"},"recipient":{"id":"123"},"timestamp":1569262485349,"message":{"mid":"890"}}]}]}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.HandlerTimeoutFor("message", 5*time.Millisecond),
+					handler.Options.InstagramMessageHandler(testHandler{func(ctx context.Context) error {
+						<-ctx.Done()
+						return ctx.Err()
+					}}),
+				}
+			},
+			expected: &handler.HandlerTimeoutError{
+				Object:  handler.Instagram,
+				EntryId: "123",
+				Kind:    "message",
+				Timeout: 5 * time.Millisecond,
+			},
+			timeout: time.Second,
+		},
+		{
+			name:   "kind override",
+			method: http.MethodPost,
+			body:   strings.NewReader(`{"object":"instagram","entry":[{"id":"123","time":1569262486134,"changes":[{"field":"mentions","value":{"media_id":"999"}}]}]}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.HandlerTimeout(time.Millisecond),
+					handler.Options.HandlerTimeoutFor("mentions", time.Second),
+					handler.Options.InstagramMentionHandler(testHandler{func(ctx context.Context) error {
+						time.Sleep(10 * time.Millisecond)
+						scenario.trigger("mention")
+						return ctx.Err()
+					}}),
+				}
+			},
+			expectedHandlers: map[string]int{
+				"mention": 1,
+			},
+			timeout: time.Second,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.test(t, func(t *testing.T) {
+			hooks, req := scenario.setup(t)
+
+			ctx, cancel := context.WithTimeout(context.Background(), scenario.timeout)
+			defer cancel()
+
+			start := time.Now()
+			_, payload, err := hooks.HandleRequest(ctx, req)
+			if elapsed := time.Since(start); elapsed >= scenario.timeout {
+				t.Errorf("Expected to return before %v, but took %v", scenario.timeout, elapsed)
+			}
+
+			if scenario.expected == nil {
+				scenario.assert(t, nil, payload, err)
+				return
+			}
+
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("Expected error %v, but got %v", context.DeadlineExceeded, err)
+			}
+
+			var timeoutErr *handler.HandlerTimeoutError
+			if !errors.As(err, &timeoutErr) {
+				t.Fatalf("Expected *HandlerTimeoutError, but got %v", err)
+			}
+
+			scenario.assert(t, timeoutErr, payload, nil)
+		})
+	}
+}
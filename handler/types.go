@@ -12,6 +12,8 @@ type (
 	Object            = gometawebhooks.Object
 	Postback          = gometawebhooks.Postback
 	Referral          = gometawebhooks.Referral
+	Reaction          = gometawebhooks.Reaction
+	Read              = gometawebhooks.Read
 	Attachment        = gometawebhooks.Attachment
 	AttachmentPayload = gometawebhooks.AttachmentPayload
 
@@ -19,21 +21,81 @@ type (
 	MessagingMessage              = gometawebhooks.MessagingMessage
 	MessagingPostback             = gometawebhooks.MessagingPostback
 	MessagingReferral             = gometawebhooks.MessagingReferral
+	MessagingReaction             = gometawebhooks.MessagingReaction
+	MessagingSeen                 = gometawebhooks.MessagingSeen
 	InstagramHandler              = gometawebhooks.InstagramHandler
 	InstagramChangesHandler       = gometawebhooks.InstagramChangesHandler
+	InstagramCommentHandler       = gometawebhooks.InstagramCommentHandler
+	InstagramLiveCommentHandler   = gometawebhooks.InstagramLiveCommentHandler
 	InstagramMentionHandler       = gometawebhooks.InstagramMentionHandler
 	InstagramMessageHandler       = gometawebhooks.InstagramMessageHandler
 	InstagramMessagingHandler     = gometawebhooks.InstagramMessagingHandler
 	InstagramPostbackHandler      = gometawebhooks.InstagramPostbackHandler
 	InstagramReferralHandler      = gometawebhooks.InstagramReferralHandler
+	InstagramReactionHandler      = gometawebhooks.InstagramReactionHandler
+	InstagramSeenHandler          = gometawebhooks.InstagramSeenHandler
 	InstagramStoryInsightsHandler = gometawebhooks.InstagramStoryInsightsHandler
+	PageHandler                   = gometawebhooks.PageHandler
+	PageMessageHandler            = gometawebhooks.PageMessageHandler
+	PageMessagingHandler          = gometawebhooks.PageMessagingHandler
+	PagePostbackHandler           = gometawebhooks.PagePostbackHandler
+	PageReferralHandler           = gometawebhooks.PageReferralHandler
+	PageReactionHandler           = gometawebhooks.PageReactionHandler
+	PageSeenHandler               = gometawebhooks.PageSeenHandler
+	WhatsAppHandler               = gometawebhooks.WhatsAppHandler
+	WhatsAppMessageHandler        = gometawebhooks.WhatsAppMessageHandler
+	WhatsAppStatusHandler         = gometawebhooks.WhatsAppStatusHandler
+
+	ChangeFieldHandler   = gometawebhooks.ChangeFieldHandler
+	MessagingTypeHandler = gometawebhooks.MessagingTypeHandler
+	RawHandler           = gometawebhooks.RawHandler
+	RawChangeHandler     = gometawebhooks.RawChangeHandler
+	RawMessagingHandler  = gometawebhooks.RawMessagingHandler
+	DedupStore           = gometawebhooks.DedupStore
+	DispatchFunc         = gometawebhooks.DispatchFunc
+	Middleware           = gometawebhooks.Middleware
+	HandlerPanicError    = gometawebhooks.HandlerPanicError
+	HandlerTimeoutError  = gometawebhooks.HandlerTimeoutError
+	DispatchError        = gometawebhooks.DispatchError
+	ItemError            = gometawebhooks.ItemError
+	SecretProvider       = gometawebhooks.SecretProvider
+	SecretProviderFunc   = gometawebhooks.SecretProviderFunc
+
+	VerifyTokenProvider     = gometawebhooks.VerifyTokenProvider
+	VerifyTokenProviderFunc = gometawebhooks.VerifyTokenProviderFunc
 
 	Mention       = gometawebhooks.Mention
 	StoryInsights = gometawebhooks.StoryInsights
+	Comment       = gometawebhooks.Comment
+	CommentFrom   = gometawebhooks.CommentFrom
+	CommentMedia  = gometawebhooks.CommentMedia
+	LiveComment   = gometawebhooks.LiveComment
+
+	WhatsAppMessages           = gometawebhooks.WhatsAppMessages
+	WhatsAppMetadata           = gometawebhooks.WhatsAppMetadata
+	WhatsAppContact            = gometawebhooks.WhatsAppContact
+	WhatsAppProfile            = gometawebhooks.WhatsAppProfile
+	WhatsAppMessage            = gometawebhooks.WhatsAppMessage
+	WhatsAppContext            = gometawebhooks.WhatsAppContext
+	WhatsAppText               = gometawebhooks.WhatsAppText
+	WhatsAppMedia              = gometawebhooks.WhatsAppMedia
+	WhatsAppLocation           = gometawebhooks.WhatsAppLocation
+	WhatsAppInteractive        = gometawebhooks.WhatsAppInteractive
+	WhatsAppReply              = gometawebhooks.WhatsAppReply
+	WhatsAppReaction           = gometawebhooks.WhatsAppReaction
+	WhatsAppStatus             = gometawebhooks.WhatsAppStatus
+	WhatsAppConversation       = gometawebhooks.WhatsAppConversation
+	WhatsAppConversationOrigin = gometawebhooks.WhatsAppConversationOrigin
+	WhatsAppPricing            = gometawebhooks.WhatsAppPricing
+	WhatsAppError              = gometawebhooks.WhatsAppError
+	WhatsAppErrorData          = gometawebhooks.WhatsAppErrorData
 )
 
 var Options = gometawebhooks.Options
 
 const (
 	Instagram = gometawebhooks.Instagram
+	Page      = gometawebhooks.Page
+
+	WhatsAppBusinessAccount = gometawebhooks.WhatsAppBusinessAccount
 )
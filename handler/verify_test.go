@@ -1,6 +1,8 @@
 package handler_test
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"testing"
 
@@ -8,6 +10,8 @@ import (
 	"github.com/pnmcosta/go-meta-webhooks/handler"
 )
 
+var errTokenStore = errors.New("token store unavailable")
+
 func TestVerify(t *testing.T) {
 	t.Parallel()
 	scenarios := []hookScenario{
@@ -19,13 +23,13 @@ func TestVerify(t *testing.T) {
 		{
 			name:      "invalid mode",
 			method:    http.MethodGet,
-			expectErr: gometawebhooks.ErrVerifyTokenFailed,
+			expectErr: gometawebhooks.ErrInvalidVerifyMode,
 		},
 		{
 			name:      "invalid verify_token",
 			url:       "/webhooks/meta/?hub.mode=subscribe",
 			method:    http.MethodGet,
-			expectErr: gometawebhooks.ErrVerifyTokenFailed,
+			expectErr: gometawebhooks.ErrVerifyTokenMismatch,
 		},
 		{
 			name:      "missing challenge",
@@ -33,6 +37,54 @@ func TestVerify(t *testing.T) {
 			method:    http.MethodGet,
 			expectErr: gometawebhooks.ErrVerifyTokenFailed,
 		},
+		{
+			name: "missing challenge with valid verify_token",
+			url:  "/webhooks/meta/?hub.mode=subscribe&hub.verify_token=meta_app_webhook_token",
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.Token("meta_app_webhook_token"),
+				}
+			},
+			method:    http.MethodGet,
+			expectErr: gometawebhooks.ErrMissingChallenge,
+		},
+		{
+			name: "empty verify_token",
+			url:  "/webhooks/meta/?hub.mode=subscribe&hub.challenge=challenge_response",
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.Token(""),
+				}
+			},
+			method:    http.MethodGet,
+			expectErr: gometawebhooks.ErrVerifyTokenMismatch,
+		},
+		{
+			name: "verifies provided tokens",
+			url:  "/webhooks/meta/?hub.mode=subscribe&hub.verify_token=new_token&hub.challenge=challenge_response",
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.VerifyTokenProvider(handler.VerifyTokenProviderFunc(func(ctx context.Context) ([]string, error) {
+						return []string{"old_token", "new_token"}, nil
+					})),
+				}
+			},
+			method:   http.MethodGet,
+			expected: "challenge_response",
+		},
+		{
+			name: "verify token provider failure",
+			url:  "/webhooks/meta/?hub.mode=subscribe&hub.verify_token=new_token&hub.challenge=challenge_response",
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.VerifyTokenProvider(handler.VerifyTokenProviderFunc(func(ctx context.Context) ([]string, error) {
+						return nil, errTokenStore
+					})),
+				}
+			},
+			method:    http.MethodGet,
+			expectErr: errTokenStore,
+		},
 		{
 			name: "verifies",
 			url:  "/webhooks/meta/?hub.mode=subscribe&hub.verify_token=meta_app_webhook_token&hub.challenge=challenge_response",
@@ -0,0 +1,359 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	gometawebhooks "github.com/pnmcosta/go-meta-webhooks"
+	"github.com/pnmcosta/go-meta-webhooks/handler"
+)
+
+func TestHandleWhatsApp(t *testing.T) {
+	t.Parallel()
+	scenarios := []hookScenario{
+		{
+			name:   "message handler not defined",
+			method: http.MethodPost,
+			body: strings.NewReader(`{
+				"object": "whatsapp_business_account",
+				"entry": [{
+					"id": "WHATSAPP_BUSINESS_ACCOUNT_ID",
+					"changes": [{
+						"field": "messages",
+						"value": {
+							"messaging_product": "whatsapp",
+							"metadata": {
+								"display_phone_number": "16505551111",
+								"phone_number_id": "123456123"
+							},
+							"messages": [{
+								"from": "16315551181",
+								"id": "wamid.ID",
+								"timestamp": "1669233778",
+								"type": "text",
+								"text": {
+									"body": "this is a text message"
+								}
+							}]
+						}
+					}]
+				}]
+			}`),
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+				}
+			},
+			expectErr: gometawebhooks.ErrWhatsAppMessageHandlerNotDefined,
+		},
+		{
+			name:   "handles messages and statuses",
+			method: http.MethodPost,
+			body: strings.NewReader(`{
+				"object": "whatsapp_business_account",
+				"entry": [{
+					"id": "WHATSAPP_BUSINESS_ACCOUNT_ID",
+					"changes": [{
+						"field": "messages",
+						"value": {
+							"messaging_product": "whatsapp",
+							"metadata": {
+								"display_phone_number": "16505551111",
+								"phone_number_id": "123456123"
+							},
+							"contacts": [{
+								"profile": {
+									"name": "Kerry Fisher"
+								},
+								"wa_id": "16315551181"
+							}],
+							"messages": [{
+								"from": "16315551181",
+								"id": "wamid.TEXT",
+								"timestamp": "1669233778",
+								"type": "text",
+								"text": {
+									"body": "this is a text message"
+								}
+							},{
+								"from": "16315551181",
+								"id": "wamid.IMAGE",
+								"timestamp": "1669233778",
+								"type": "image",
+								"image": {
+									"caption": "This is a caption",
+									"mime_type": "image/jpeg",
+									"sha256": "81d3bd8a8db4868c9520ed47186e8b7c5789e61ff79f7f834be6950b808a90d3",
+									"id": "2754859441498128"
+								}
+							},{
+								"from": "16315551181",
+								"id": "wamid.LOCATION",
+								"timestamp": "1669233778",
+								"type": "location",
+								"location": {
+									"latitude": 38.9,
+									"longitude": -77.03,
+									"name": "Main Street",
+									"address": "1 Main Street"
+								}
+							},{
+								"context": {
+									"from": "16505551111",
+									"id": "wamid.ORIGINAL"
+								},
+								"from": "16315551181",
+								"id": "wamid.BUTTON",
+								"timestamp": "1669233778",
+								"type": "interactive",
+								"interactive": {
+									"type": "button_reply",
+									"button_reply": {
+										"id": "unique-button-identifier-here",
+										"title": "button-text"
+									}
+								}
+							},{
+								"from": "16315551181",
+								"id": "wamid.REACTION",
+								"timestamp": "1669233778",
+								"type": "reaction",
+								"reaction": {
+									"message_id": "wamid.ORIGINAL",
+									"emoji": "❤️"
+								}
+							}],
+							"statuses": [{
+								"id": "wamid.SENT",
+								"status": "delivered",
+								"timestamp": "1669233778",
+								"recipient_id": "16315551181",
+								"conversation": {
+									"id": "CONVERSATION_ID",
+									"expiration_timestamp": "1669320178",
+									"origin": {
+										"type": "service"
+									}
+								},
+								"pricing": {
+									"billable": true,
+									"pricing_model": "CBP",
+									"category": "service"
+								}
+							}]
+						}
+					}]
+				}]
+			}`),
+			expected: handler.Event{
+				Object: handler.WhatsAppBusinessAccount,
+				Entry: []handler.Entry{{
+					Id: "WHATSAPP_BUSINESS_ACCOUNT_ID",
+					Changes: []handler.Change{{
+						Field: "messages",
+						Value: handler.WhatsAppMessages{
+							MessagingProduct: "whatsapp",
+							Metadata: handler.WhatsAppMetadata{
+								DisplayPhoneNumber: "16505551111",
+								PhoneNumberId:      "123456123",
+							},
+							Contacts: []handler.WhatsAppContact{{
+								WaId: "16315551181",
+								Profile: handler.WhatsAppProfile{
+									Name: "Kerry Fisher",
+								},
+							}},
+							Messages: []handler.WhatsAppMessage{{
+								Id:        "wamid.TEXT",
+								From:      "16315551181",
+								Timestamp: "1669233778",
+								Type:      "text",
+								Text: &handler.WhatsAppText{
+									Body: "this is a text message",
+								},
+							}, {
+								Id:        "wamid.IMAGE",
+								From:      "16315551181",
+								Timestamp: "1669233778",
+								Type:      "image",
+								Image: &handler.WhatsAppMedia{
+									Id:       "2754859441498128",
+									MimeType: "image/jpeg",
+									Sha256:   "81d3bd8a8db4868c9520ed47186e8b7c5789e61ff79f7f834be6950b808a90d3",
+									Caption:  "This is a caption",
+								},
+							}, {
+								Id:        "wamid.LOCATION",
+								From:      "16315551181",
+								Timestamp: "1669233778",
+								Type:      "location",
+								Location: &handler.WhatsAppLocation{
+									Latitude:  38.9,
+									Longitude: -77.03,
+									Name:      "Main Street",
+									Address:   "1 Main Street",
+								},
+							}, {
+								Id:        "wamid.BUTTON",
+								From:      "16315551181",
+								Timestamp: "1669233778",
+								Type:      "interactive",
+								Context: &handler.WhatsAppContext{
+									From: "16505551111",
+									Id:   "wamid.ORIGINAL",
+								},
+								Interactive: &handler.WhatsAppInteractive{
+									Type: "button_reply",
+									ButtonReply: &handler.WhatsAppReply{
+										Id:    "unique-button-identifier-here",
+										Title: "button-text",
+									},
+								},
+							}, {
+								Id:        "wamid.REACTION",
+								From:      "16315551181",
+								Timestamp: "1669233778",
+								Type:      "reaction",
+								Reaction: &handler.WhatsAppReaction{
+									MessageId: "wamid.ORIGINAL",
+									Emoji:     "❤️",
+								},
+							}},
+							Statuses: []handler.WhatsAppStatus{{
+								Id:          "wamid.SENT",
+								Status:      "delivered",
+								Timestamp:   "1669233778",
+								RecipientId: "16315551181",
+								Conversation: &handler.WhatsAppConversation{
+									Id:                  "CONVERSATION_ID",
+									ExpirationTimestamp: "1669320178",
+									Origin: &handler.WhatsAppConversationOrigin{
+										Type: "service",
+									},
+								},
+								Pricing: &handler.WhatsAppPricing{
+									Billable:     true,
+									PricingModel: "CBP",
+									Category:     "service",
+								},
+							}},
+						},
+					}},
+				}},
+			},
+			options: func(scenario *hookScenario) []handler.Option {
+				return []handler.Option{
+					handler.Options.CompileSchema(),
+					handler.Options.WhatsAppMessageHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("message")
+						return nil
+					}}),
+					handler.Options.WhatsAppStatusHandler(testHandler{func(ctx context.Context) error {
+						scenario.trigger("status")
+						return nil
+					}}),
+				}
+			},
+			expectedHandlers: map[string]int{
+				"message": 5,
+				"status":  1,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.test(t, func(t *testing.T) {
+			hooks, req := scenario.setup(t)
+
+			ctx, cancel := context.WithTimeout(context.Background(), scenario.timeout)
+			defer cancel()
+
+			result, payload, err := hooks.HandleRequest(ctx, req)
+
+			scenario.assert(t, result, payload, err)
+		})
+	}
+}
+
+var errWhatsAppMessage = errors.New("whatsapp message failed")
+
+func TestWhatsAppDispatch(t *testing.T) {
+	t.Parallel()
+
+	body := `{"object":"whatsapp_business_account","entry":[{"id":"WHATSAPP_BUSINESS_ACCOUNT_ID","changes":[{"field":"messages","value":{"messaging_product":"whatsapp","metadata":{"display_phone_number":"16505551111","phone_number_id":"123456123"},"messages":[{"from":"16315551181","id":"wamid.1","timestamp":"1669233778","type":"text","text":{"body":"one"}},{"from":"16315551181","id":"wamid.2","timestamp":"1669233778","type":"text","text":{"body":"two"}}],"statuses":[{"id":"wamid.0","status":"delivered","timestamp":"1669233778","recipient_id":"16315551181"}]}}]}]}`
+
+	var (
+		mu    sync.Mutex
+		items []string
+	)
+	middleware := func(next handler.DispatchFunc) handler.DispatchFunc {
+		return func(ctx context.Context, object handler.Object, entry handler.Entry, item interface{}) error {
+			mu.Lock()
+			switch item := item.(type) {
+			case handler.WhatsAppMessage:
+				items = append(items, item.Id)
+			case handler.WhatsAppStatus:
+				items = append(items, item.Id+":"+item.Status)
+			}
+			mu.Unlock()
+			return next(ctx, object, entry, item)
+		}
+	}
+
+	hooks, err := handler.New(
+		handler.Options.ContinueOnError(true),
+		handler.Options.MaxConcurrency(1),
+		handler.Options.Use(middleware),
+		handler.Options.WhatsAppMessageHandler(whatsAppHandler{func(message handler.WhatsAppMessage) error {
+			if message.Id == "wamid.2" {
+				return errWhatsAppMessage
+			}
+			return nil
+		}}),
+		handler.Options.WhatsAppStatusHandler(whatsAppHandler{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event, err := hooks.ParsePayload([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = hooks.Handle(context.Background(), event)
+
+	var dispatchErr *handler.DispatchError
+	if !errors.As(err, &dispatchErr) || len(dispatchErr.Errors) != 1 {
+		t.Fatalf("Expected *DispatchError with one item, but got %v", err)
+	}
+
+	expected := handler.ItemError{EntryId: "WHATSAPP_BUSINESS_ACCOUNT_ID", Index: 0, Kind: "messages", Item: 1, Err: errWhatsAppMessage}
+	if result := *dispatchErr.Errors[0]; result != expected {
+		t.Errorf("Expected %+v, but got %+v", expected, result)
+	}
+
+	slices.Sort(items)
+	if expected := []string{"wamid.0:delivered", "wamid.1", "wamid.2"}; !slices.Equal(items, expected) {
+		t.Errorf("Expected middleware items %v, but got %v", expected, items)
+	}
+}
+
+type whatsAppHandler struct {
+	message func(message handler.WhatsAppMessage) error
+}
+
+// WhatsAppMessage implements handler.WhatsAppMessageHandler.
+func (h whatsAppHandler) WhatsAppMessage(ctx context.Context, object handler.Object, entry handler.Entry, value handler.WhatsAppMessages, message handler.WhatsAppMessage) error {
+	return h.message(message)
+}
+
+// WhatsAppStatus implements handler.WhatsAppStatusHandler.
+func (h whatsAppHandler) WhatsAppStatus(ctx context.Context, object handler.Object, entry handler.Entry, value handler.WhatsAppMessages, status handler.WhatsAppStatus) error {
+	return nil
+}
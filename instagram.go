@@ -16,6 +16,14 @@ type InstagramReferralHandler interface {
 	InstagramReferral(ctx context.Context, object Object, entry Entry, referral MessagingReferral) error
 }
 
+type InstagramReactionHandler interface {
+	InstagramReaction(ctx context.Context, object Object, entry Entry, reaction MessagingReaction) error
+}
+
+type InstagramSeenHandler interface {
+	InstagramSeen(ctx context.Context, object Object, entry Entry, seen MessagingSeen) error
+}
+
 type InstagramMentionHandler interface {
 	InstagramMention(ctx context.Context, object Object, entry Entry, mention Mention) error
 }
@@ -24,6 +32,16 @@ type InstagramStoryInsightsHandler interface {
 	InstagramStoryInsights(ctx context.Context, object Object, entry Entry, storyInsights StoryInsights) error
 }
 
+type InstagramCommentHandler interface {
+	InstagramComment(ctx context.Context, object Object, entry Entry, comment Comment) error
+}
+
+type InstagramLiveCommentHandler interface {
+	InstagramLiveComment(ctx context.Context, object Object, entry Entry, liveComment LiveComment) error
+}
+
+// Handles Instagram mentions and story insights, implement InstagramCommentHandler and
+// InstagramLiveCommentHandler too to also handle comments and live comments
 type InstagramChangesHandler interface {
 	InstagramMentionHandler
 	InstagramStoryInsightsHandler
@@ -33,9 +51,49 @@ type InstagramMessagingHandler interface {
 	InstagramMessageHandler
 	InstagramPostbackHandler
 	InstagramReferralHandler
+	InstagramReactionHandler
+	InstagramSeenHandler
 }
 
 type InstagramHandler interface {
 	InstagramChangesHandler
 	InstagramMessagingHandler
 }
+
+func (h Webhooks) instagramMessage(ctx context.Context, object Object, entry Entry, messaging Messaging) error {
+	switch value := messaging.Type.(type) {
+	case MessagingMessage:
+		if h.instagramMessageHandler == nil {
+			return ErrInstagramMessageHandlerNotDefined
+		}
+
+		return h.instagramMessageHandler.InstagramMessage(ctx, object, entry, value)
+	case MessagingPostback:
+		if h.instagramPostbackHandler == nil {
+			return ErrInstagramPostbackHandlerNotDefined
+		}
+
+		return h.instagramPostbackHandler.InstagramPostback(ctx, object, entry, value)
+	case MessagingReferral:
+		if h.instagramReferralHandler == nil {
+			return ErrInstagramReferralHandlerNotDefined
+		}
+
+		return h.instagramReferralHandler.InstagramReferral(ctx, object, entry, value)
+	case MessagingReaction:
+		if h.instagramReactionHandler == nil {
+			return ErrInstagramReactionHandlerNotDefined
+		}
+
+		return h.instagramReactionHandler.InstagramReaction(ctx, object, entry, value)
+	case MessagingSeen:
+		if h.instagramSeenHandler == nil {
+			return ErrInstagramSeenHandlerNotDefined
+		}
+
+		return h.instagramSeenHandler.InstagramSeen(ctx, object, entry, value)
+	default:
+		// @note should not be hit cause Unmarshall ensures field is supported
+		return ErrMessagingTypeNotImplemented
+	}
+}
@@ -0,0 +1,118 @@
+package gometawebhooks
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+)
+
+var discardLogger = slog.New(discardHandler{})
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// implemented by messaging types embedding MessagingHeader
+type messagingHeader interface {
+	header() MessagingHeader
+}
+
+func (h MessagingHeader) header() MessagingHeader {
+	return h
+}
+
+func (h Webhooks) log() *slog.Logger {
+	if h.logger == nil {
+		return discardLogger
+	}
+	return h.logger
+}
+
+// Attributes identifying a change, messaging item or WhatsApp message or status, values are only included when payload logging is enabled
+func (h Webhooks) itemAttrs(object Object, entry Entry, item interface{}) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("object", object.String()),
+		slog.String("entry_id", entry.Id),
+	}
+
+	var value interface{}
+	switch item := item.(type) {
+	case Change:
+		attrs = append(attrs, slog.String("field", item.Field))
+		value = item.Value
+		if item.Raw != nil {
+			value = item.Raw
+		}
+	case Messaging:
+		attrs = append(attrs, slog.String("kind", item.Kind()))
+		if header, ok := item.Type.(messagingHeader); ok {
+			attrs = append(attrs, slog.String("sender_id", header.header().Sender.Id))
+		}
+		value = item.Type
+		if item.Raw != nil {
+			value = item.Raw
+		}
+	case WhatsAppMessage:
+		attrs = append(attrs, slog.String("kind", "messages"), slog.String("message_id", item.Id))
+		value = item
+	case WhatsAppStatus:
+		attrs = append(attrs, slog.String("kind", "statuses"), slog.String("message_id", item.Id))
+		value = item
+	}
+
+	if h.logPayloads {
+		if b, err := json.Marshal(value); err == nil {
+			attrs = append(attrs, slog.String("value", string(b)))
+		}
+	}
+
+	return attrs
+}
+
+// Logs a verify, validate or parse step at debug level, or warn level when it failed
+func (h Webhooks) logPayload(ctx context.Context, step string, start time.Time, body []byte, err error, attrs ...slog.Attr) {
+	level := slog.LevelDebug
+	if err != nil {
+		level = slog.LevelWarn
+	}
+
+	logger := h.log()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+	if h.logPayloads {
+		attrs = append(attrs, slog.String("payload", string(body)))
+	}
+
+	if err != nil {
+		logger.LogAttrs(ctx, level, step+" payload failed", append(attrs, slog.Any("error", err))...)
+		return
+	}
+	logger.LogAttrs(ctx, level, step+" payload", attrs...)
+}
+
+func (h Webhooks) logDispatch(ctx context.Context, object Object, entry Entry, item interface{}, elapsed time.Duration, err error) {
+	level := slog.LevelDebug
+	msg := "dispatched"
+	if err != nil {
+		level = slog.LevelError
+		msg = "handler failed"
+	}
+
+	logger := h.log()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := append(h.itemAttrs(object, entry, item), slog.Duration("duration", elapsed))
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	logger.LogAttrs(ctx, level, msg, attrs...)
+}
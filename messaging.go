@@ -4,8 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-
-	"golang.org/x/sync/errgroup"
+	"fmt"
 )
 
 var (
@@ -13,6 +12,8 @@ var (
 	ErrInstagramMessageHandlerNotDefined  = errors.New("instagram message handler not defined")
 	ErrInstagramPostbackHandlerNotDefined = errors.New("instagram postback handler not defined")
 	ErrInstagramReferralHandlerNotDefined = errors.New("instagram referral handler not defined")
+	ErrInstagramReactionHandlerNotDefined = errors.New("instagram reaction handler not defined")
+	ErrInstagramSeenHandlerNotDefined     = errors.New("instagram seen handler not defined")
 )
 
 type Message struct {
@@ -68,6 +69,18 @@ type Postback struct {
 	Referral *Referral `json:"referral,omitempty"`
 }
 
+type Reaction struct {
+	Id       string `json:"mid,omitempty"`
+	Action   string `json:"action,omitempty"`
+	Reaction string `json:"reaction,omitempty"`
+	Emoji    string `json:"emoji,omitempty"`
+}
+
+type Read struct {
+	Id        string `json:"mid,omitempty"`
+	Watermark int64  `json:"watermark,omitempty"`
+}
+
 type MessagingHeader struct {
 	Sender struct {
 		Id string `json:"id"`
@@ -99,79 +112,211 @@ type MessagingReferral struct {
 	Referral Referral `json:"referral,omitempty"`
 }
 
-// @todo these
 // https://developers.facebook.com/docs/messenger-platform/instagram/features/webhook/#message-reactions
+type MessagingReaction struct {
+	MessagingHeader
+
+	Reaction Reaction `json:"reaction,omitempty"`
+}
+
 // https://developers.facebook.com/docs/messenger-platform/instagram/features/webhook/#messaging-seen
+type MessagingSeen struct {
+	MessagingHeader
+
+	Read Read `json:"read,omitempty"`
+}
 
-// Wrapper struct for types MessagingMessage, MessagingPostback and MessagingReferral
+// Wrapper struct for types MessagingMessage, MessagingPostback, MessagingReferral, MessagingReaction and MessagingSeen
 type Messaging struct {
 	Type interface{} `json:"-"`
+
+	// undecoded item of unsupported objects and types, see Options.RawMessagingHandler
+	Raw json.RawMessage `json:"-"`
+
+	// registered messaging type key, see Options.MessagingType
+	key string
 }
 
 func (t *Messaging) UnmarshalJSON(b []byte) error {
-	var message MessagingMessage
-	if err := json.Unmarshal(b, &message); err == nil && message.Message.Id != "" {
-		t.Type = message
-		return nil
+	value, err := messagingType(b)
+	if err != nil {
+		return err
 	}
 
-	var postback MessagingPostback
-	if err := json.Unmarshal(b, &postback); err == nil && postback.Postback.Id != "" {
-		t.Type = postback
-		return nil
+	// @note unsupported types are only kept undecoded by Webhooks.ParsePayload, see Options.RawMessagingHandler
+	if value == nil {
+		return ErrMessagingTypeNotImplemented
 	}
 
-	var referral MessagingReferral
-	if err := json.Unmarshal(b, &referral); err == nil && referral.Referral.Type != "" {
-		t.Type = referral
-		return nil
+	*t = Messaging{Type: value}
+	return nil
+}
+
+// Messaging item of any supported type, discriminated by key presence
+type messagingUnion struct {
+	MessagingHeader
+
+	Message  *Message  `json:"message"`
+	Postback *Postback `json:"postback"`
+	Referral *Referral `json:"referral"`
+	Reaction *Reaction `json:"reaction"`
+	Read     *Read     `json:"read"`
+}
+
+// Returns the supported type of the item, nil for other types
+func (item messagingUnion) value() interface{} {
+	switch {
+	case item.Message != nil:
+		return MessagingMessage{item.MessagingHeader, *item.Message}
+	case item.Postback != nil:
+		return MessagingPostback{item.MessagingHeader, *item.Postback}
+	case item.Referral != nil:
+		return MessagingReferral{item.MessagingHeader, *item.Referral}
+	case item.Reaction != nil:
+		return MessagingReaction{item.MessagingHeader, *item.Reaction}
+	case item.Read != nil:
+		return MessagingSeen{item.MessagingHeader, *item.Read}
+	}
+	return nil
+}
+
+// Decodes supported types discriminated by key presence in a single pass, returns nil for other types
+func messagingType(b []byte) (interface{}, error) {
+	var item messagingUnion
+	if err := json.Unmarshal(b, &item); err != nil {
+		return nil, err
+	}
+	return item.value(), nil
+}
+
+// Resolves the messaging item node of the decoded payload against registered and raw handlers, ensures its type
+// is supported, undecoded returns the undecoded item for registered and raw handlers
+func (hooks Webhooks) parseMessaging(object Object, node interface{}, undecoded func() (json.RawMessage, error)) (Messaging, error) {
+	var messaging Messaging
+
+	if key, fn := hooks.messagingTypeKey(node); fn != nil {
+		raw, err := undecoded()
+		if err != nil {
+			return messaging, err
+		}
+		value, err := fn.DecodeMessaging(object, key, raw)
+		if err != nil {
+			return messaging, err
+		}
+		messaging.Type = value
+		messaging.key = key
+		return messaging, nil
+	}
+
+	var item messagingUnion
+	if err := assignTree(node, &item); err != nil {
+		return messaging, err
+	}
+	value := item.value()
+
+	if hooks.rawMessagingHandler != nil && (value == nil || !object.supported()) {
+		raw, err := undecoded()
+		if err != nil {
+			return messaging, err
+		}
+		messaging.Raw = raw
+		return messaging, nil
+	}
+
+	if !object.supported() {
+		return messaging, fmt.Errorf("'%s': %w", object, ErrObjectNotSupported)
+	}
+
+	if value == nil {
+		return messaging, ErrMessagingTypeNotImplemented
+	}
+
+	messaging.Type = value
+	return messaging, nil
+}
+
+// Marshals the decoded type, or the undecoded item of unsupported types, keeping the original fields
+func (t Messaging) MarshalJSON() ([]byte, error) {
+	if t.Raw != nil {
+		return t.Raw, nil
+	}
+	return json.Marshal(t.Type)
+}
+
+// Returns the messaging type discriminator, e.g. message, postback, referral, reaction, read, a registered key or raw
+func (t Messaging) Kind() string {
+	switch t.Type.(type) {
+	case MessagingMessage:
+		return "message"
+	case MessagingPostback:
+		return "postback"
+	case MessagingReferral:
+		return "referral"
+	case MessagingReaction:
+		return "reaction"
+	case MessagingSeen:
+		return "read"
+	}
+
+	if t.key != "" {
+		return t.key
 	}
 
-	return ErrMessagingTypeNotImplemented
+	if t.Raw != nil {
+		return "raw"
+	}
+	return ""
 }
 
-func (hooks Webhooks) messaging(ctx context.Context, object Object, entry Entry) error {
+func (hooks Webhooks) messaging(ctx context.Context, b *batch, position int, object Object, entry Entry) error {
 	if len(entry.Messaging) == 0 {
 		return nil
 	}
 
-	g, ctx := errgroup.WithContext(ctx)
+	g, ctx := b.group(ctx)
 
 	g.SetLimit(len(entry.Messaging))
-	for _, messaging := range entry.Messaging {
+	for i := range entry.Messaging {
+		p, err := b.acquire(ctx)
+		if err != nil {
+			g.Go(func() error { return err })
+			break
+		}
+
 		g.Go(func() error {
-			return hooks.message(ctx, object, entry, messaging)
+			defer p.release()
+			return hooks.messagingItem(ctx, b, p, position, object, entry, i)
 		})
 	}
 	return g.Wait()
 }
 
-func (h Webhooks) message(ctx context.Context, object Object, entry Entry, messaging Messaging) error {
-	switch value := messaging.Type.(type) {
-	case MessagingMessage:
-		if h.ignoreEchoMessages && value.Message.IsEcho {
-			return nil
-		}
+// Dispatches the messaging item at index of entry
+func (hooks Webhooks) messagingItem(ctx context.Context, b *batch, p *permit, position int, object Object, entry Entry, index int) error {
+	messaging := entry.Messaging[index]
+	err := hooks.dispatch(ctx, p, object, entry, messaging, func(ctx context.Context) error {
+		return hooks.message(ctx, object, entry, messaging)
+	})
+	return b.fail(position, entry, index, messaging, err)
+}
 
-		if h.instagramMessageHandler == nil {
-			return ErrInstagramMessageHandlerNotDefined
-		}
+func (h Webhooks) message(ctx context.Context, object Object, entry Entry, messaging Messaging) error {
+	if fn, ok := h.messagingTypes[messaging.key]; ok {
+		return fn.HandleMessaging(ctx, object, entry, messaging)
+	}
 
-		return h.instagramMessageHandler.InstagramMessage(ctx, object, entry, value)
-	case MessagingPostback:
-		if h.instagramPostbackHandler == nil {
-			return ErrInstagramPostbackHandlerNotDefined
-		}
+	if messaging.Raw != nil && h.rawMessagingHandler != nil {
+		return h.rawMessagingHandler.RawMessaging(ctx, object, entry, messaging)
+	}
 
-		return h.instagramPostbackHandler.InstagramPostback(ctx, object, entry, value)
-	case MessagingReferral:
-		if h.instagramReferralHandler == nil {
-			return ErrInstagramReferralHandlerNotDefined
-		}
+	if value, ok := messaging.Type.(MessagingMessage); ok && h.ignoreEchoMessages && value.Message.IsEcho {
+		return nil
+	}
 
-		return h.instagramReferralHandler.InstagramReferral(ctx, object, entry, value)
+	switch object {
+	case Page:
+		return h.pageMessage(ctx, object, entry, messaging)
 	default:
-		// @note should not be hit cause Unmarshall ensures field is supported
-		return ErrMessagingTypeNotImplemented
+		return h.instagramMessage(ctx, object, entry, messaging)
 	}
 }
@@ -0,0 +1,36 @@
+package gometawebhooks
+
+import "context"
+
+// DispatchFunc handles a typed change value, messaging type or WhatsApp message or status of an entry, e.g. Mention,
+// MessagingMessage or WhatsAppMessage, unsupported pieces preserved by raw handlers are passed as Change or Messaging.
+type DispatchFunc func(ctx context.Context, object Object, entry Entry, item interface{}) error
+
+// Middleware wraps the dispatch of every change, messaging item and WhatsApp message or status, see Options.Use
+type Middleware func(next DispatchFunc) DispatchFunc
+
+func dispatchItem(item interface{}) interface{} {
+	switch item := item.(type) {
+	case Change:
+		if item.Raw == nil {
+			return item.Value
+		}
+	case Messaging:
+		if item.Raw == nil {
+			return item.Type
+		}
+	}
+	return item
+}
+
+// Composes middlewares in order around fn, the first middleware being the outermost
+func (h Webhooks) chain(fn func(context.Context) error) DispatchFunc {
+	next := DispatchFunc(func(ctx context.Context, object Object, entry Entry, item interface{}) error {
+		return fn(ctx)
+	})
+
+	for i := len(h.middlewares) - 1; i >= 0; i-- {
+		next = h.middlewares[i](next)
+	}
+	return next
+}
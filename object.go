@@ -10,6 +10,9 @@ type Object string
 
 const (
 	Instagram Object = "instagram"
+	Page      Object = "page"
+
+	WhatsAppBusinessAccount Object = "whatsapp_business_account"
 )
 
 var (
@@ -18,6 +21,9 @@ var (
 
 	supportedObjects = map[string]Object{
 		"instagram": Instagram,
+		"page":      Page,
+
+		"whatsapp_business_account": WhatsAppBusinessAccount,
 	}
 )
 
@@ -25,6 +31,11 @@ func (t Object) String() string {
 	return string(t)
 }
 
+func (t Object) supported() bool {
+	_, ok := supportedObjects[string(t)]
+	return ok
+}
+
 func (t *Object) FromString(status string) Object {
 	return supportedObjects[status]
 }
@@ -1,5 +1,17 @@
 package gometawebhooks
 
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"golang.org/x/sync/semaphore"
+)
+
 // Option is a configuration option for the webhook
 type Option func(*Webhooks) error
 
@@ -12,7 +24,36 @@ type MetaWebhookOptions struct{}
 // Sets the Facebook APP Secret
 func (MetaWebhookOptions) Secret(secret string) Option {
 	return func(hooks *Webhooks) error {
-		hooks.secret = secret
+		hooks.secrets = nil
+		if secret != "" {
+			hooks.secrets = staticSecrets{secret}
+		}
+		return nil
+	}
+}
+
+// Sets multiple active Facebook APP Secrets, payloads signed with any of them are verified, e.g. while rotating
+func (MetaWebhookOptions) Secrets(secrets ...string) Option {
+	return func(hooks *Webhooks) error {
+		var active staticSecrets
+		for _, secret := range secrets {
+			if secret != "" {
+				active = append(active, secret)
+			}
+		}
+
+		hooks.secrets = nil
+		if len(active) > 0 {
+			hooks.secrets = active
+		}
+		return nil
+	}
+}
+
+// Sets the provider of active Facebook APP Secrets, called for each verified payload
+func (MetaWebhookOptions) SecretProvider(provider SecretProvider) Option {
+	return func(hooks *Webhooks) error {
+		hooks.secrets = provider
 		return nil
 	}
 }
@@ -20,7 +61,15 @@ func (MetaWebhookOptions) Secret(secret string) Option {
 // Sets the Facebook APP webhook subscription verify token
 func (MetaWebhookOptions) Token(token string) Option {
 	return func(hooks *Webhooks) error {
-		hooks.token = token
+		hooks.tokens = staticTokens{token}
+		return nil
+	}
+}
+
+// Sets the provider of active webhook subscription verify tokens, called for each verification
+func (MetaWebhookOptions) VerifyTokenProvider(provider VerifyTokenProvider) Option {
+	return func(hooks *Webhooks) error {
+		hooks.tokens = provider
 		return nil
 	}
 }
@@ -32,12 +81,232 @@ func (MetaWebhookOptions) IgnoreEchoMessages(ignore bool) Option {
 	}
 }
 
-// Ensures embedded JSON schema is compiled
+// Registers a handler to decode and handle a custom changes field of an object, takes precedence over built-in fields
+func (MetaWebhookOptions) ChangeField(object Object, field string, fn ChangeFieldHandler) Option {
+	return func(hooks *Webhooks) error {
+		if object == "" || field == "" {
+			return fmt.Errorf("change field '%s' of '%s': %w", field, object, ErrRegistryKeyRequired)
+		}
+		if fn == nil {
+			return fmt.Errorf("change field '%s' of '%s': %w", field, object, ErrRegistryHandlerRequired)
+		}
+
+		if hooks.changeFields == nil {
+			hooks.changeFields = make(map[changeField]ChangeFieldHandler)
+		}
+		hooks.changeFields[changeField{object, field}] = fn
+		return nil
+	}
+}
+
+// Registers a handler to decode and handle messaging items containing key, takes precedence over built-in types
+func (MetaWebhookOptions) MessagingType(key string, fn MessagingTypeHandler) Option {
+	return func(hooks *Webhooks) error {
+		if key == "" {
+			return fmt.Errorf("messaging type: %w", ErrRegistryKeyRequired)
+		}
+		if fn == nil {
+			return fmt.Errorf("messaging type '%s': %w", key, ErrRegistryHandlerRequired)
+		}
+
+		if hooks.messagingTypes == nil {
+			hooks.messagingTypes = make(map[string]MessagingTypeHandler)
+		}
+		if _, ok := hooks.messagingTypes[key]; !ok {
+			hooks.messagingKeys = append(hooks.messagingKeys, key)
+			sort.Strings(hooks.messagingKeys)
+		}
+		hooks.messagingTypes[key] = fn
+		return nil
+	}
+}
+
+// Sets the DedupStore used to skip changes and messaging already handled, e.g. redelivered by Meta
+func (MetaWebhookOptions) Deduplicate(store DedupStore) Option {
+	return func(hooks *Webhooks) error {
+		hooks.dedupStore = store
+		return nil
+	}
+}
+
+// Sets the logger for verification, validation, parsing and dispatch steps, defaults to discarding logs
+func (MetaWebhookOptions) Logger(logger *slog.Logger) Option {
+	return func(hooks *Webhooks) error {
+		hooks.logger = logger
+		return nil
+	}
+}
+
+// Includes payloads and item values in logs, disabled by default as they may contain personal data
+func (MetaWebhookOptions) LogPayloads(enabled bool) Option {
+	return func(hooks *Webhooks) error {
+		hooks.logPayloads = enabled
+		return nil
+	}
+}
+
+// Appends middlewares wrapping the dispatch of every change, messaging item and WhatsApp message or status, composed in order
+func (MetaWebhookOptions) Use(middlewares ...Middleware) Option {
+	return func(hooks *Webhooks) error {
+		hooks.middlewares = append(hooks.middlewares, middlewares...)
+		return nil
+	}
+}
+
+// Sets whether handler panics are recovered and returned by Handle as a *HandlerPanicError, enabled by default,
+// when disabled the first *HandlerPanicError is re-panicked on the goroutine calling Handle, or on the handler goroutine
+// when a handler outliving its timeout panics after Handle returned.
+func (MetaWebhookOptions) RecoverPanics(recover bool) Option {
+	return func(hooks *Webhooks) error {
+		hooks.recoverPanics = recover
+		return nil
+	}
+}
+
+// Sets whether Handle runs every item to completion when one fails, returning a *DispatchError listing
+// each failed item, instead of cancelling the remaining items and returning the first error.
+func (MetaWebhookOptions) ContinueOnError(continueOnError bool) Option {
+	return func(hooks *Webhooks) error {
+		hooks.continueOnError = continueOnError
+		return nil
+	}
+}
+
+// Limits the number of items dispatched concurrently by each Handle call, across all of its entries
+func (MetaWebhookOptions) MaxConcurrency(n int) Option {
+	return func(hooks *Webhooks) error {
+		hooks.maxConcurrency = int64(n)
+		return nil
+	}
+}
+
+// Limits the number of items dispatched concurrently across all Handle calls of the webhooks instance
+func (MetaWebhookOptions) GlobalMaxConcurrency(n int) Option {
+	return func(hooks *Webhooks) error {
+		hooks.sharedSemaphore = nil
+		if n > 0 {
+			hooks.sharedSemaphore = semaphore.NewWeighted(int64(n))
+		}
+		return nil
+	}
+}
+
+// Sets whether messaging items of the same recipient and sender are dispatched sequentially in timestamp order,
+// across all entries of an event, while different conversations are still dispatched concurrently.
+func (MetaWebhookOptions) OrderedConversations(ordered bool) Option {
+	return func(hooks *Webhooks) error {
+		hooks.orderedConversations = ordered
+		return nil
+	}
+}
+
+// Sets the timeout of each handler invocation, exceeding it returns a *HandlerTimeoutError, handlers should return
+// once their ctx is done as those which don't keep running, and counting towards MaxConcurrency, until they return
+func (MetaWebhookOptions) HandlerTimeout(timeout time.Duration) Option {
+	return func(hooks *Webhooks) error {
+		hooks.defaultHandlerTimeout = timeout
+		return nil
+	}
+}
+
+// Overrides the handler timeout for a changes field or messaging kind, e.g. story_insights, message or statuses
+func (MetaWebhookOptions) HandlerTimeoutFor(kind string, timeout time.Duration) Option {
+	return func(hooks *Webhooks) error {
+		if hooks.handlerTimeouts == nil {
+			hooks.handlerTimeouts = make(map[string]time.Duration)
+		}
+		hooks.handlerTimeouts[kind] = timeout
+		return nil
+	}
+}
+
+// Validates payloads against the embedded JSON schema, compiled once and shared by instances
 func (MetaWebhookOptions) CompileSchema() Option {
 	return func(hooks *Webhooks) error {
-		if err := hooks.compileSchema(); err != nil {
+		schema, err := embeddedSchema()
+		if err != nil {
+			return err
+		}
+		hooks.schema = schema
+		return nil
+	}
+}
+
+// Validates payloads against the JSON schema read from r instead of the embedded one, e.g. a stricter schema
+func (MetaWebhookOptions) Schema(r io.Reader) Option {
+	return func(hooks *Webhooks) error {
+		schema, err := compileSchema("schema.json", r)
+		if err != nil {
+			return err
+		}
+		hooks.schema = schema
+		return nil
+	}
+}
+
+// Validates payloads against the JSON schema at path of fsys instead of the embedded one
+func (MetaWebhookOptions) SchemaFS(fsys fs.FS, path string) Option {
+	return func(hooks *Webhooks) error {
+		schema, err := compileSchemaFS(fsys, path)
+		if err != nil {
 			return err
 		}
+		hooks.schema = schema
+		return nil
+	}
+}
+
+// Validates payloads of object against the JSON schema read from r instead of the payload schema,
+// e.g. objects not covered by the embedded schema
+func (MetaWebhookOptions) ObjectSchema(object Object, r io.Reader) Option {
+	return func(hooks *Webhooks) error {
+		schema, err := compileSchema(object.String()+".json", r)
+		if err != nil {
+			return fmt.Errorf("'%s': %w", object, err)
+		}
+
+		if hooks.objectSchemas == nil {
+			hooks.objectSchemas = map[Object]*jsonschema.Schema{}
+		}
+		hooks.objectSchemas[object] = schema
+		return nil
+	}
+}
+
+// Rejects events with entry or messaging timestamps older than maxAge or later than maxSkew from now with ErrStaleEvent,
+// a zero duration disables the respective check. whatsapp_business_account entries omit time and aren't checked,
+// use ReplayCache to reject their replays instead
+func (MetaWebhookOptions) ReplayWindow(maxAge time.Duration, maxSkew time.Duration) Option {
+	return func(hooks *Webhooks) error {
+		hooks.maxEventAge = maxAge
+		hooks.maxEventSkew = maxSkew
+		return nil
+	}
+}
+
+// Sets whether events outside the replay window are parsed and flagged as Event.Stale instead of rejected,
+// handlers tell stale events with IsStale(ctx)
+func (MetaWebhookOptions) FlagStaleEvents(flag bool) Option {
+	return func(hooks *Webhooks) error {
+		hooks.flagStaleEvents = flag
+		return nil
+	}
+}
+
+// Records verified payload signatures in store and rejects payloads with a signature already seen with ErrStaleEvent,
+// see Webhooks.RecordSignature. As Meta redelivers failed events with the same signature the handler package forgets
+// signatures of events which fail to be handled or dispatched, see Webhooks.ForgetSignature.
+func (MetaWebhookOptions) ReplayCache(store DedupStore) Option {
+	return func(hooks *Webhooks) error {
+		hooks.replayCache = store
+		return nil
+	}
+}
+
+// Limits the size of request payloads read, both before and after decompression, larger payloads fail with ErrPayloadTooLarge
+func (MetaWebhookOptions) MaxBodyBytes(n int64) Option {
+	return func(hooks *Webhooks) error {
+		hooks.maxBodyBytes = n
 		return nil
 	}
 }
@@ -49,3 +318,11 @@ func (MetaWebhookOptions) CustomHeaderSigName(name string) Option {
 		return nil
 	}
 }
+
+// Sets whether the SHA-1 X-Hub-Signature header is verified when the signature header is missing
+func (MetaWebhookOptions) LegacySignature(enabled bool) Option {
+	return func(hooks *Webhooks) error {
+		hooks.legacySignature = enabled
+		return nil
+	}
+}
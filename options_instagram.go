@@ -16,6 +16,22 @@ func (MetaWebhookOptions) InstagramStoryInsightsHandler(fn InstagramStoryInsight
 	}
 }
 
+// Sets the InstagramCommentHandler, see https://developers.facebook.com/docs/instagram-platform/webhooks/examples#comments
+func (MetaWebhookOptions) InstagramCommentHandler(fn InstagramCommentHandler) Option {
+	return func(hooks *Webhooks) error {
+		hooks.instagramCommentHandler = fn
+		return nil
+	}
+}
+
+// Sets the InstagramLiveCommentHandler, see https://developers.facebook.com/docs/instagram-platform/webhooks/examples#live-comments
+func (MetaWebhookOptions) InstagramLiveCommentHandler(fn InstagramLiveCommentHandler) Option {
+	return func(hooks *Webhooks) error {
+		hooks.instagramLiveCommentHandler = fn
+		return nil
+	}
+}
+
 // Sets the InstagramMessageHandler, see https://developers.facebook.com/docs/messenger-platform/instagram/features/webhook/#messages
 func (MetaWebhookOptions) InstagramMessageHandler(fn InstagramMessageHandler) Option {
 	return func(hooks *Webhooks) error {
@@ -40,33 +56,65 @@ func (MetaWebhookOptions) InstagramReferralHandler(fn InstagramReferralHandler)
 	}
 }
 
+// Sets the InstagramReactionHandler, see https://developers.facebook.com/docs/messenger-platform/instagram/features/webhook/#message-reactions
+func (MetaWebhookOptions) InstagramReactionHandler(fn InstagramReactionHandler) Option {
+	return func(hooks *Webhooks) error {
+		hooks.instagramReactionHandler = fn
+		return nil
+	}
+}
+
+// Sets the InstagramSeenHandler, see https://developers.facebook.com/docs/messenger-platform/instagram/features/webhook/#messaging-seen
+func (MetaWebhookOptions) InstagramSeenHandler(fn InstagramSeenHandler) Option {
+	return func(hooks *Webhooks) error {
+		hooks.instagramSeenHandler = fn
+		return nil
+	}
+}
+
 // Sets all InstagramMessaging handlers
 func (MetaWebhookOptions) InstagramMessagingHandler(fn InstagramMessagingHandler) Option {
 	return func(hooks *Webhooks) error {
 		hooks.instagramMessageHandler = fn
 		hooks.instagramPostbackHandler = fn
 		hooks.instagramReferralHandler = fn
+		hooks.instagramReactionHandler = fn
+		hooks.instagramSeenHandler = fn
 		return nil
 	}
 }
 
-// Sets all InstagramChanges handlers
+// Sets all InstagramChanges handlers, and the comment handlers fn implements
 func (MetaWebhookOptions) InstagramChangesHandler(fn InstagramChangesHandler) Option {
 	return func(hooks *Webhooks) error {
 		hooks.instagramMentionHandler = fn
 		hooks.instagramStoryInsightsHandler = fn
+		hooks.instagramComments(fn)
 		return nil
 	}
 }
 
-// Sets all Instagram handlers
+// Sets all Instagram handlers, and the comment handlers fn implements
 func (MetaWebhookOptions) InstagramHandler(fn InstagramHandler) Option {
 	return func(hooks *Webhooks) error {
 		hooks.instagramMessageHandler = fn
 		hooks.instagramPostbackHandler = fn
 		hooks.instagramReferralHandler = fn
+		hooks.instagramReactionHandler = fn
+		hooks.instagramSeenHandler = fn
 		hooks.instagramMentionHandler = fn
 		hooks.instagramStoryInsightsHandler = fn
+		hooks.instagramComments(fn)
 		return nil
 	}
 }
+
+// Sets the optional comment handlers implemented by fn
+func (hooks *Webhooks) instagramComments(fn interface{}) {
+	if handler, ok := fn.(InstagramCommentHandler); ok {
+		hooks.instagramCommentHandler = handler
+	}
+	if handler, ok := fn.(InstagramLiveCommentHandler); ok {
+		hooks.instagramLiveCommentHandler = handler
+	}
+}
@@ -0,0 +1,65 @@
+package gometawebhooks
+
+// Sets the PageMessageHandler, see https://developers.facebook.com/docs/messenger-platform/reference/webhook-events/messages
+func (MetaWebhookOptions) PageMessageHandler(fn PageMessageHandler) Option {
+	return func(hooks *Webhooks) error {
+		hooks.pageMessageHandler = fn
+		return nil
+	}
+}
+
+// Sets the PagePostbackHandler, see https://developers.facebook.com/docs/messenger-platform/reference/webhook-events/messaging_postbacks
+func (MetaWebhookOptions) PagePostbackHandler(fn PagePostbackHandler) Option {
+	return func(hooks *Webhooks) error {
+		hooks.pagePostbackHandler = fn
+		return nil
+	}
+}
+
+// Sets the PageReferralHandler, see https://developers.facebook.com/docs/messenger-platform/reference/webhook-events/messaging_referrals
+func (MetaWebhookOptions) PageReferralHandler(fn PageReferralHandler) Option {
+	return func(hooks *Webhooks) error {
+		hooks.pageReferralHandler = fn
+		return nil
+	}
+}
+
+// Sets the PageReactionHandler, see https://developers.facebook.com/docs/messenger-platform/reference/webhook-events/message-reactions
+func (MetaWebhookOptions) PageReactionHandler(fn PageReactionHandler) Option {
+	return func(hooks *Webhooks) error {
+		hooks.pageReactionHandler = fn
+		return nil
+	}
+}
+
+// Sets the PageSeenHandler, see https://developers.facebook.com/docs/messenger-platform/reference/webhook-events/message-reads
+func (MetaWebhookOptions) PageSeenHandler(fn PageSeenHandler) Option {
+	return func(hooks *Webhooks) error {
+		hooks.pageSeenHandler = fn
+		return nil
+	}
+}
+
+// Sets all PageMessaging handlers
+func (MetaWebhookOptions) PageMessagingHandler(fn PageMessagingHandler) Option {
+	return func(hooks *Webhooks) error {
+		hooks.pageMessageHandler = fn
+		hooks.pagePostbackHandler = fn
+		hooks.pageReferralHandler = fn
+		hooks.pageReactionHandler = fn
+		hooks.pageSeenHandler = fn
+		return nil
+	}
+}
+
+// Sets all Page handlers
+func (MetaWebhookOptions) PageHandler(fn PageHandler) Option {
+	return func(hooks *Webhooks) error {
+		hooks.pageMessageHandler = fn
+		hooks.pagePostbackHandler = fn
+		hooks.pageReferralHandler = fn
+		hooks.pageReactionHandler = fn
+		hooks.pageSeenHandler = fn
+		return nil
+	}
+}
@@ -0,0 +1,26 @@
+package gometawebhooks
+
+// Sets the RawChangeHandler, unsupported objects and changes fields are preserved and handled instead of failing
+func (MetaWebhookOptions) RawChangeHandler(fn RawChangeHandler) Option {
+	return func(hooks *Webhooks) error {
+		hooks.rawChangeHandler = fn
+		return nil
+	}
+}
+
+// Sets the RawMessagingHandler, unsupported objects and messaging types are preserved and handled instead of failing
+func (MetaWebhookOptions) RawMessagingHandler(fn RawMessagingHandler) Option {
+	return func(hooks *Webhooks) error {
+		hooks.rawMessagingHandler = fn
+		return nil
+	}
+}
+
+// Sets all Raw handlers
+func (MetaWebhookOptions) RawHandler(fn RawHandler) Option {
+	return func(hooks *Webhooks) error {
+		hooks.rawChangeHandler = fn
+		hooks.rawMessagingHandler = fn
+		return nil
+	}
+}
@@ -0,0 +1,26 @@
+package gometawebhooks
+
+// Sets the WhatsAppMessageHandler, see https://developers.facebook.com/docs/whatsapp/cloud-api/webhooks/components#messages-object
+func (MetaWebhookOptions) WhatsAppMessageHandler(fn WhatsAppMessageHandler) Option {
+	return func(hooks *Webhooks) error {
+		hooks.whatsAppMessageHandler = fn
+		return nil
+	}
+}
+
+// Sets the WhatsAppStatusHandler, see https://developers.facebook.com/docs/whatsapp/cloud-api/webhooks/components#statuses-object
+func (MetaWebhookOptions) WhatsAppStatusHandler(fn WhatsAppStatusHandler) Option {
+	return func(hooks *Webhooks) error {
+		hooks.whatsAppStatusHandler = fn
+		return nil
+	}
+}
+
+// Sets all WhatsApp handlers
+func (MetaWebhookOptions) WhatsAppHandler(fn WhatsAppHandler) Option {
+	return func(hooks *Webhooks) error {
+		hooks.whatsAppMessageHandler = fn
+		hooks.whatsAppStatusHandler = fn
+		return nil
+	}
+}
@@ -0,0 +1,84 @@
+package gometawebhooks
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrPageMessageHandlerNotDefined  = errors.New("page message handler not defined")
+	ErrPagePostbackHandlerNotDefined = errors.New("page postback handler not defined")
+	ErrPageReferralHandlerNotDefined = errors.New("page referral handler not defined")
+	ErrPageReactionHandlerNotDefined = errors.New("page reaction handler not defined")
+	ErrPageSeenHandlerNotDefined     = errors.New("page seen handler not defined")
+)
+
+type PageMessageHandler interface {
+	PageMessage(ctx context.Context, object Object, entry Entry, message MessagingMessage) error
+}
+
+type PagePostbackHandler interface {
+	PagePostback(ctx context.Context, object Object, entry Entry, postback MessagingPostback) error
+}
+
+type PageReferralHandler interface {
+	PageReferral(ctx context.Context, object Object, entry Entry, referral MessagingReferral) error
+}
+
+type PageReactionHandler interface {
+	PageReaction(ctx context.Context, object Object, entry Entry, reaction MessagingReaction) error
+}
+
+type PageSeenHandler interface {
+	PageSeen(ctx context.Context, object Object, entry Entry, seen MessagingSeen) error
+}
+
+type PageMessagingHandler interface {
+	PageMessageHandler
+	PagePostbackHandler
+	PageReferralHandler
+	PageReactionHandler
+	PageSeenHandler
+}
+
+type PageHandler interface {
+	PageMessagingHandler
+}
+
+func (h Webhooks) pageMessage(ctx context.Context, object Object, entry Entry, messaging Messaging) error {
+	switch value := messaging.Type.(type) {
+	case MessagingMessage:
+		if h.pageMessageHandler == nil {
+			return ErrPageMessageHandlerNotDefined
+		}
+
+		return h.pageMessageHandler.PageMessage(ctx, object, entry, value)
+	case MessagingPostback:
+		if h.pagePostbackHandler == nil {
+			return ErrPagePostbackHandlerNotDefined
+		}
+
+		return h.pagePostbackHandler.PagePostback(ctx, object, entry, value)
+	case MessagingReferral:
+		if h.pageReferralHandler == nil {
+			return ErrPageReferralHandlerNotDefined
+		}
+
+		return h.pageReferralHandler.PageReferral(ctx, object, entry, value)
+	case MessagingReaction:
+		if h.pageReactionHandler == nil {
+			return ErrPageReactionHandlerNotDefined
+		}
+
+		return h.pageReactionHandler.PageReaction(ctx, object, entry, value)
+	case MessagingSeen:
+		if h.pageSeenHandler == nil {
+			return ErrPageSeenHandlerNotDefined
+		}
+
+		return h.pageSeenHandler.PageSeen(ctx, object, entry, value)
+	default:
+		// @note should not be hit cause Unmarshall ensures field is supported
+		return ErrMessagingTypeNotImplemented
+	}
+}
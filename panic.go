@@ -0,0 +1,57 @@
+package gometawebhooks
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// HandlerPanicError is returned by Handle when a handler or middleware panics while dispatching an item
+type HandlerPanicError struct {
+	Object  Object
+	EntryId string
+	Kind    string
+	Value   interface{}
+	Stack   []byte
+}
+
+func (e *HandlerPanicError) Error() string {
+	return fmt.Sprintf("panic handling '%s' of '%s' entry '%s': %v", e.Kind, e.Object, e.EntryId, e.Value)
+}
+
+// Unwraps the panic value when it is an error
+func (e *HandlerPanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Deferred by dispatch goroutines, converts panics into a HandlerPanicError assigned to err and recorded on b
+func recoverPanic(b *batch, object Object, entry Entry, kind string, err *error) {
+	if v := recover(); v != nil {
+		panicErr := &HandlerPanicError{
+			Object:  object,
+			EntryId: entry.Id,
+			Kind:    kind,
+			Value:   v,
+			Stack:   debug.Stack(),
+		}
+		*err = panicErr
+		b.recovered(panicErr)
+	}
+}
+
+// Returns the changes field or messaging kind of an item, messages or statuses for WhatsApp items
+func itemKind(item interface{}) string {
+	switch item := item.(type) {
+	case Change:
+		return item.Field
+	case Messaging:
+		return item.Kind()
+	case WhatsAppMessage:
+		return "messages"
+	case WhatsAppStatus:
+		return "statuses"
+	}
+	return ""
+}
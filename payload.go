@@ -1,68 +1,203 @@
 package gometawebhooks
 
 import (
-	"crypto/hmac"
+	"context"
+	"crypto/sha1"
 	"crypto/sha256"
-	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
 )
 
 const (
 	HeaderSignatureName = "X-Hub-Signature-256"
+	// SHA-1 signature header, see Options.LegacySignature
+	HeaderLegacySignatureName = "X-Hub-Signature"
 )
 
 var (
 	ErrMissingHubSignatureHeader = errors.New("missing signature value")
 	ErrHMACVerificationFailed    = errors.New("HMAC verification failed")
+	ErrMalformedSignature        = errors.New("malformed signature")
+	ErrSignatureMismatch         = errors.New("signature mismatch")
+	ErrSecretProvider            = errors.New("error providing secrets")
 	ErrParsingPayload            = errors.New("parsing payload")
 	ErrInvalidPayload            = errors.New("invalid payload")
 )
 
+// Validates body against the schema of its object and parses it into an event, see DecodePayloadContext
+func (hooks Webhooks) DecodePayload(body []byte) (Event, error) {
+	return hooks.DecodePayloadContext(context.Background(), body)
+}
+
+// Validates body against the schema of its object and parses it into an event from the same decoded payload,
+// decoding body only once, logging the outcome of both steps with ctx
+func (hooks Webhooks) DecodePayloadContext(ctx context.Context, body []byte) (Event, error) {
+	d, err := hooks.validatePayload(ctx, body)
+	if err != nil {
+		return Event{}, err
+	}
+	return hooks.parsePayload(ctx, body, d)
+}
+
+// Parses body into an event of its object, see ParsePayloadContext
 func (hooks Webhooks) ParsePayload(body []byte) (Event, error) {
-	var event Event
-	if err := json.Unmarshal(body, &event); err != nil {
+	return hooks.ParsePayloadContext(context.Background(), body)
+}
+
+// Parses body into an event of its object, logging the outcome with ctx
+func (hooks Webhooks) ParsePayloadContext(ctx context.Context, body []byte) (Event, error) {
+	return hooks.parsePayload(ctx, body, nil)
+}
+
+// Parses the decoded payload d of body, decoding body when d is nil
+func (hooks Webhooks) parsePayload(ctx context.Context, body []byte, d *decodedPayload) (event Event, err error) {
+	defer func(start time.Time) {
+		hooks.logPayload(ctx, "parse", start, body, err,
+			slog.String("object", event.Object.String()),
+			slog.Int("entries", len(event.Entry)),
+		)
+	}(time.Now())
+
+	if d == nil {
+		if d, err = decodePayload(body); err != nil {
+			return event, wrapErr(err, ErrParsingPayload)
+		}
+	}
+
+	if event, err = hooks.parseEvent(d); err != nil {
 		return event, wrapErr(err, ErrParsingPayload)
 	}
+
+	if err := hooks.checkReplayWindow(time.Now(), event); err != nil {
+		if !hooks.flagStaleEvents {
+			return event, err
+		}
+		event.Stale = true
+	}
 	return event, nil
 }
 
+// Validates body against the schema of its object, see ValidatePayloadContext
 func (hooks Webhooks) ValidatePayload(body []byte) error {
-	if validationSchema == nil {
-		return ErrMissingSchema
+	return hooks.ValidatePayloadContext(context.Background(), body)
+}
+
+// Validates body against the schema of its object, logging the outcome with ctx
+func (hooks Webhooks) ValidatePayloadContext(ctx context.Context, body []byte) error {
+	_, err := hooks.validatePayload(ctx, body)
+	return err
+}
+
+// Validates body against the schema of its object, returns the decoded payload for parsing
+func (hooks Webhooks) validatePayload(ctx context.Context, body []byte) (d *decodedPayload, err error) {
+	defer func(start time.Time) {
+		hooks.logPayload(ctx, "validate", start, body, err)
+	}(time.Now())
+
+	if hooks.schema == nil && len(hooks.objectSchemas) == 0 {
+		return nil, ErrMissingSchema
+	}
+
+	if d, err = decodePayload(body); err != nil {
+		return nil, wrapErr(err, ErrParsingPayload)
 	}
 
-	var pl interface{}
-	if err := json.Unmarshal(body, &pl); err != nil {
-		return wrapErr(err, ErrParsingPayload)
+	var object string
+	if root, ok := d.root.(map[string]interface{}); ok {
+		object, _ = root["object"].(string)
 	}
 
-	if err := validationSchema.Validate(pl); err != nil {
-		return wrapErr(err, ErrInvalidPayload)
+	schema, err := hooks.schemaFor(object)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	if err := schema.Validate(d.root); err != nil {
+		return nil, wrapErr(err, ErrInvalidPayload)
+	}
+
+	return d, nil
 }
 
+// Verifies the payload signature found in headers, header names are matched case-insensitively
 func (hooks Webhooks) VerifyPayload(body []byte, headers map[string]string) error {
+	return hooks.VerifyPayloadContext(context.Background(), body, headers)
+}
+
+// Verifies the payload signature found in headers, ctx is passed to the SecretProvider
+func (hooks Webhooks) VerifyPayloadContext(ctx context.Context, body []byte, headers map[string]string) error {
+	return hooks.verifyPayload(ctx, body, func(name string) string {
+		if value, ok := headers[name]; ok {
+			return value
+		}
+		for k, v := range headers {
+			if strings.EqualFold(k, name) {
+				return v
+			}
+		}
+		return ""
+	})
+}
+
+// Verifies the payload signature found in the request header
+func (hooks Webhooks) VerifyPayloadHeader(ctx context.Context, body []byte, header http.Header) error {
+	return hooks.VerifyPayloadContext(ctx, body, headerValues(header))
+}
+
+// Verifies the payload signature found in the request header, returns the index of the secret it was signed with,
+// e.g. to tell when the previous App Secret is no longer used while rotating, or -1 when no secret is set
+func (hooks Webhooks) VerifyPayloadSecret(ctx context.Context, body []byte, header http.Header) (int, error) {
+	return hooks.verifyPayloadSecret(ctx, body, header.Get)
+}
+
+func (hooks Webhooks) verifyPayload(ctx context.Context, body []byte, header func(name string) string) error {
+	_, err := hooks.verifyPayloadSecret(ctx, body, header)
+	return err
+}
+
+// Returns the name and value of the signature header, or of the legacy header when missing and enabled
+func (hooks Webhooks) signatureHeader(header func(name string) string) (string, string, bool) {
+	if signature := header(hooks.headerSigName); len(signature) > 0 || !hooks.legacySignature {
+		return hooks.headerSigName, signature, false
+	}
+	return HeaderLegacySignatureName, header(HeaderLegacySignatureName), true
+}
+
+func (hooks Webhooks) verifyPayloadSecret(ctx context.Context, body []byte, header func(name string) string) (matched int, err error) {
+	name := hooks.headerSigName
+	matched = -1
+	defer func(start time.Time) {
+		hooks.logPayload(ctx, "verify", start, nil, err, slog.String("header", name), slog.Int("secret_index", matched))
+	}(time.Now())
+
 	// If we have a Secret set, we should check the MAC
 	// https://developers.facebook.com/docs/messenger-platform/webhooks#validate-payloads
-	if len(hooks.secret) == 0 {
-		return nil
+	if hooks.secrets == nil {
+		return matched, nil
 	}
 
-	signature := headers[hooks.headerSigName]
-	if len(signature) == 0 {
-		return fmt.Errorf("missing %s Header: %w", hooks.headerSigName, ErrMissingHubSignatureHeader)
+	secrets, err := hooks.secrets.Secrets(ctx)
+	if err != nil {
+		return matched, wrapErr(err, ErrSecretProvider)
+	}
+	if len(secrets) == 0 {
+		return matched, fmt.Errorf("no active secrets: %w", ErrSecretProvider)
 	}
 
-	mac := hmac.New(sha256.New, []byte(hooks.secret))
-	mac.Write(body)
-	expectedMAC := hex.EncodeToString(mac.Sum(nil))
+	hash, prefix := sha256.New, "sha256="
+	name, signature, legacy := hooks.signatureHeader(header)
+	if legacy {
+		hash, prefix = sha1.New, "sha1="
+	}
 
-	if len(signature) <= 8 || !hmac.Equal([]byte(signature[7:]), []byte(expectedMAC)) {
-		return ErrHMACVerificationFailed
+	if len(signature) == 0 {
+		return matched, fmt.Errorf("missing %s Header: %w", hooks.headerSigName, ErrMissingHubSignatureHeader)
 	}
-	return nil
+
+	matched, err = verifySignature(body, signature, prefix, hash, secrets)
+	return matched, err
 }
@@ -0,0 +1,22 @@
+package gometawebhooks
+
+import "context"
+
+// Handles changes with fields not supported by the package, see Change.Raw
+type RawChangeHandler interface {
+	RawChange(ctx context.Context, object Object, entry Entry, change Change) error
+}
+
+// Handles messaging with types not supported by the package, see Messaging.Raw
+type RawMessagingHandler interface {
+	RawMessaging(ctx context.Context, object Object, entry Entry, messaging Messaging) error
+}
+
+type RawHandler interface {
+	RawChangeHandler
+	RawMessagingHandler
+}
+
+func (hooks Webhooks) preserveUnknown() bool {
+	return hooks.rawChangeHandler != nil || hooks.rawMessagingHandler != nil
+}
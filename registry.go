@@ -0,0 +1,91 @@
+package gometawebhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrRegistryKeyRequired     = errors.New("registry key required")
+	ErrRegistryHandlerRequired = errors.New("registry handler required")
+)
+
+// ChangeFieldHandler decodes and handles a custom changes field value registered for an object
+type ChangeFieldHandler interface {
+	DecodeChange(object Object, field string, value json.RawMessage) (interface{}, error)
+	HandleChange(ctx context.Context, object Object, entry Entry, change Change) error
+}
+
+// MessagingTypeHandler decodes and handles a custom messaging item discriminated by a key
+type MessagingTypeHandler interface {
+	DecodeMessaging(object Object, key string, messaging json.RawMessage) (interface{}, error)
+	HandleMessaging(ctx context.Context, object Object, entry Entry, messaging Messaging) error
+}
+
+type changeField struct {
+	object Object
+	field  string
+}
+
+type changeFieldFunc[T any] func(ctx context.Context, object Object, entry Entry, value T) error
+
+// Creates a ChangeFieldHandler which decodes the change value into T and calls fn
+func ChangeFieldFunc[T any](fn func(ctx context.Context, object Object, entry Entry, value T) error) ChangeFieldHandler {
+	return changeFieldFunc[T](fn)
+}
+
+func (fn changeFieldFunc[T]) DecodeChange(object Object, field string, value json.RawMessage) (interface{}, error) {
+	var v T
+	if err := json.Unmarshal(value, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (fn changeFieldFunc[T]) HandleChange(ctx context.Context, object Object, entry Entry, change Change) error {
+	value, ok := change.Value.(T)
+	if !ok {
+		return fmt.Errorf("'%s': %w", change.Field, ErrChangesFieldNotImplemented)
+	}
+	return fn(ctx, object, entry, value)
+}
+
+type messagingTypeFunc[T any] func(ctx context.Context, object Object, entry Entry, value T) error
+
+// Creates a MessagingTypeHandler which decodes the whole messaging item into T and calls fn
+func MessagingTypeFunc[T any](fn func(ctx context.Context, object Object, entry Entry, value T) error) MessagingTypeHandler {
+	return messagingTypeFunc[T](fn)
+}
+
+func (fn messagingTypeFunc[T]) DecodeMessaging(object Object, key string, messaging json.RawMessage) (interface{}, error) {
+	var v T
+	if err := json.Unmarshal(messaging, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (fn messagingTypeFunc[T]) HandleMessaging(ctx context.Context, object Object, entry Entry, messaging Messaging) error {
+	value, ok := messaging.Type.(T)
+	if !ok {
+		return fmt.Errorf("'%s': %w", messaging.key, ErrMessagingTypeNotImplemented)
+	}
+	return fn(ctx, object, entry, value)
+}
+
+// Returns the first registered messaging type key present in the messaging item node, in key order
+func (hooks Webhooks) messagingTypeKey(node interface{}) (string, MessagingTypeHandler) {
+	fields, ok := node.(map[string]interface{})
+	if len(hooks.messagingTypes) == 0 || !ok {
+		return "", nil
+	}
+
+	for _, key := range hooks.messagingKeys {
+		if _, ok := fields[key]; ok {
+			return key, hooks.messagingTypes[key]
+		}
+	}
+	return "", nil
+}
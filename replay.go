@@ -0,0 +1,106 @@
+package gometawebhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrStaleEvent        = errors.New("stale event")
+	ErrReplayedSignature = errors.New("replayed signature")
+)
+
+// Returns ErrStaleEvent when any entry or messaging timestamp is outside the replay window
+func (hooks Webhooks) checkReplayWindow(now time.Time, event Event) error {
+	if hooks.maxEventAge <= 0 && hooks.maxEventSkew <= 0 {
+		return nil
+	}
+
+	for _, entry := range event.Entry {
+		// whatsapp_business_account entries omit time
+		if entry.Time != 0 {
+			if err := hooks.inReplayWindow(now, entry.At()); err != nil {
+				return fmt.Errorf("entry '%s' %w", entry.Id, err)
+			}
+		}
+
+		for _, messaging := range entry.Messaging {
+			if header := messaging.header(); header.Timestamp != 0 {
+				if err := hooks.inReplayWindow(now, header.At()); err != nil {
+					return fmt.Errorf("entry '%s' messaging %w", entry.Id, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (hooks Webhooks) inReplayWindow(now time.Time, t time.Time) error {
+	if hooks.maxEventAge > 0 && now.Sub(t) > hooks.maxEventAge {
+		return fmt.Errorf("timestamp %s older than %s: %w", t.Format(time.RFC3339), hooks.maxEventAge, ErrStaleEvent)
+	}
+	if hooks.maxEventSkew > 0 && t.Sub(now) > hooks.maxEventSkew {
+		return fmt.Errorf("timestamp %s later than %s: %w", t.Format(time.RFC3339), hooks.maxEventSkew, ErrStaleEvent)
+	}
+	return nil
+}
+
+type staleKey struct{}
+
+// Reports whether the event being handled is outside the replay window, see Options.FlagStaleEvents
+func IsStale(ctx context.Context) bool {
+	stale, _ := ctx.Value(staleKey{}).(bool)
+	return stale
+}
+
+// Records the signature found in header in the replay cache, errors wrap ErrReplayedSignature and ErrStaleEvent when
+// it was already recorded, call it once per request after verifying the payload, see Options.ReplayCache
+func (hooks Webhooks) RecordSignature(ctx context.Context, header http.Header) error {
+	// signatures are only verified with a secret set
+	if hooks.replayCache == nil || hooks.secrets == nil {
+		return nil
+	}
+
+	_, signature, _ := hooks.signatureHeader(header.Get)
+	if len(signature) == 0 {
+		return nil
+	}
+	return hooks.checkReplayCache(ctx, signature)
+}
+
+// Records the verified signature, errors wrap ErrReplayedSignature and ErrStaleEvent when it was already seen
+func (hooks Webhooks) checkReplayCache(ctx context.Context, signature string) error {
+	seen, err := hooks.replayCache.Seen(ctx, "signature:"+signature)
+	if err != nil {
+		return wrapErr(err, ErrDeduplicating)
+	}
+	if seen {
+		return wrapErr(ErrReplayedSignature, ErrStaleEvent)
+	}
+
+	if committer, ok := hooks.replayCache.(DedupCommitter); ok {
+		_ = committer.Commit(ctx, "signature:"+signature)
+	}
+	return nil
+}
+
+// Removes the verified signature found in header from the replay cache, so that Meta's redelivery of a payload
+// which failed to be handled isn't rejected with ErrReplayedSignature, see Options.ReplayCache
+func (hooks Webhooks) ForgetSignature(ctx context.Context, header http.Header) error {
+	if hooks.replayCache == nil {
+		return nil
+	}
+
+	_, signature, _ := hooks.signatureHeader(header.Get)
+	if len(signature) == 0 {
+		return nil
+	}
+
+	if err := hooks.replayCache.Forget(ctx, "signature:"+signature); err != nil {
+		return wrapErr(err, ErrDeduplicating)
+	}
+	return nil
+}
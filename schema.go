@@ -3,6 +3,10 @@ package gometawebhooks
 import (
 	_ "embed"
 	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
 	"sync"
 
 	"github.com/santhosh-tekuri/jsonschema/v5"
@@ -10,26 +14,48 @@ import (
 
 var (
 	//go:embed schema.json
-	embedSchema      string
-	validationSchema *jsonschema.Schema
+	embedSchema string
 
 	ErrSchemaCompile = errors.New("failed to compile schema")
-	ErrMissingSchema = errors.New("missing embedded schema")
+	ErrMissingSchema = errors.New("missing schema")
 
-	mu sync.RWMutex
+	// compiled once and shared, compiled schemas are safe for concurrent use
+	embeddedSchema = sync.OnceValues(func() (*jsonschema.Schema, error) {
+		return compileSchema("schema.json", strings.NewReader(embedSchema))
+	})
 )
 
-func (hooks *Webhooks) compileSchema() error {
-	mu.Lock()
-	defer mu.Unlock()
-	if validationSchema != nil {
-		return nil
+func compileSchema(name string, r io.Reader) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(name, r); err != nil {
+		return nil, wrapErr(err, ErrSchemaCompile)
 	}
 
-	schema, err := jsonschema.CompileString("schema.json", embedSchema)
+	schema, err := compiler.Compile(name)
 	if err != nil {
-		return wrapErr(err, ErrSchemaCompile)
+		return nil, wrapErr(err, ErrSchemaCompile)
 	}
-	validationSchema = schema
-	return nil
+	return schema, nil
+}
+
+func compileSchemaFS(fsys fs.FS, path string) (*jsonschema.Schema, error) {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return nil, wrapErr(err, ErrSchemaCompile)
+	}
+	defer f.Close()
+
+	return compileSchema(path, f)
+}
+
+// Returns the sub-schema of object if set, otherwise the payload schema
+func (hooks Webhooks) schemaFor(object string) (*jsonschema.Schema, error) {
+	if schema, ok := hooks.objectSchemas[Object(object)]; ok {
+		return schema, nil
+	}
+
+	if hooks.schema == nil {
+		return nil, fmt.Errorf("'%s': %w", object, ErrMissingSchema)
+	}
+	return hooks.schema, nil
 }
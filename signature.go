@@ -0,0 +1,62 @@
+package gometawebhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"strings"
+)
+
+// SecretProvider returns the currently active Facebook APP Secrets, called for each verified payload
+type SecretProvider interface {
+	Secrets(ctx context.Context) ([]string, error)
+}
+
+// SecretProviderFunc adapts a function to a SecretProvider
+type SecretProviderFunc func(ctx context.Context) ([]string, error)
+
+func (fn SecretProviderFunc) Secrets(ctx context.Context) ([]string, error) {
+	return fn(ctx)
+}
+
+type staticSecrets []string
+
+func (s staticSecrets) Secrets(ctx context.Context) ([]string, error) {
+	return s, nil
+}
+
+// Verifies signature is prefix followed by the hex encoded HMAC of body using any of secrets and returns the index of the matching one,
+// errors wrap both ErrHMACVerificationFailed and ErrMalformedSignature or ErrSignatureMismatch
+func verifySignature(body []byte, signature string, prefix string, h func() hash.Hash, secrets []string) (int, error) {
+	digest, ok := strings.CutPrefix(signature, prefix)
+	if !ok {
+		return -1, wrapErr(ErrMalformedSignature, ErrHMACVerificationFailed)
+	}
+
+	actualMAC, err := hex.DecodeString(digest)
+	if err != nil || len(actualMAC) != h().Size() {
+		return -1, wrapErr(ErrMalformedSignature, ErrHMACVerificationFailed)
+	}
+
+	for i, secret := range secrets {
+		mac := hmac.New(h, []byte(secret))
+		mac.Write(body)
+		if hmac.Equal(actualMAC, mac.Sum(nil)) {
+			return i, nil
+		}
+	}
+	return -1, wrapErr(ErrSignatureMismatch, ErrHMACVerificationFailed)
+}
+
+// Returns the first value of each header
+func headerValues(header http.Header) map[string]string {
+	values := make(map[string]string, len(header))
+	for k, v := range header {
+		if len(v) > 0 {
+			values[k] = v[0]
+		}
+	}
+	return values
+}
@@ -0,0 +1,51 @@
+package gometawebhooks
+
+import (
+	"strconv"
+	"time"
+)
+
+// Converts a unix timestamp in seconds or milliseconds to time, as Meta sends entry times of changes in seconds and
+// of messaging in milliseconds, zero timestamps are converted to the zero time
+func unixTime(timestamp int64) time.Time {
+	switch {
+	case timestamp == 0:
+		return time.Time{}
+	// milliseconds since 2001 or seconds from year 33658
+	case timestamp >= 1e12:
+		return time.UnixMilli(timestamp)
+	default:
+		return time.Unix(timestamp, 0)
+	}
+}
+
+// Converts a unix timestamp string in seconds, as sent by whatsapp_business_account, to time
+func unixTimeString(timestamp string) time.Time {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || seconds == 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
+
+// Returns the entry time, sent in seconds for changes and in milliseconds for messaging
+func (t Entry) At() time.Time {
+	return unixTime(t.Time)
+}
+
+// Returns the messaging timestamp
+func (t MessagingHeader) At() time.Time {
+	return unixTime(t.Timestamp)
+}
+
+func (t WhatsAppMessage) At() time.Time {
+	return unixTimeString(t.Timestamp)
+}
+
+func (t WhatsAppStatus) At() time.Time {
+	return unixTimeString(t.Timestamp)
+}
+
+func (t WhatsAppConversation) ExpiresAt() time.Time {
+	return unixTimeString(t.ExpirationTimestamp)
+}
@@ -0,0 +1,66 @@
+package gometawebhooks
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// HandlerTimeoutError is returned by Handle when dispatching an item exceeds its handler timeout
+type HandlerTimeoutError struct {
+	Object  Object
+	EntryId string
+	Kind    string
+	Timeout time.Duration
+}
+
+func (e *HandlerTimeoutError) Error() string {
+	return fmt.Sprintf("handling '%s' of '%s' entry '%s' exceeded %s timeout", e.Kind, e.Object, e.EntryId, e.Timeout)
+}
+
+func (e *HandlerTimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+func (h Webhooks) handlerTimeout(kind string) time.Duration {
+	if timeout, ok := h.handlerTimeouts[kind]; ok {
+		return timeout
+	}
+	return h.defaultHandlerTimeout
+}
+
+// Runs run bounded by the handler timeout of kind, returns once the deadline is exceeded even if run ignores ctx,
+// in which case p is held until run returns so that it still counts towards the concurrency limits
+func (h Webhooks) withTimeout(ctx context.Context, p *permit, object Object, entry Entry, kind string, run func(context.Context) error) error {
+	timeout := h.handlerTimeout(kind)
+	if timeout <= 0 {
+		return run(ctx)
+	}
+
+	timeoutErr := &HandlerTimeoutError{
+		Object:  object,
+		EntryId: entry.Id,
+		Kind:    kind,
+		Timeout: timeout,
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, timeout, timeoutErr)
+	defer cancel()
+
+	done := make(chan error, 1)
+	p.hold()
+	go func() {
+		defer p.release()
+		done <- run(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil && context.Cause(ctx) == error(timeoutErr) {
+			return timeoutErr
+		}
+		return err
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
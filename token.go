@@ -1,17 +1,74 @@
 package gometawebhooks
 
-import "errors"
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+)
 
 var (
-	ErrVerifyTokenFailed = errors.New("invalid verify_token value")
+	ErrVerifyTokenFailed   = errors.New("invalid verify_token value")
+	ErrInvalidVerifyMode   = errors.New("invalid hub.mode value")
+	ErrMissingChallenge    = errors.New("missing hub.challenge value")
+	ErrVerifyTokenMismatch = errors.New("verify_token mismatch")
+	ErrVerifyTokenProvider = errors.New("error providing verify tokens")
 )
 
+// VerifyTokenProvider returns the currently active webhook subscription verify tokens, called for each verification
+type VerifyTokenProvider interface {
+	VerifyTokens(ctx context.Context) ([]string, error)
+}
+
+// VerifyTokenProviderFunc adapts a function to a VerifyTokenProvider
+type VerifyTokenProviderFunc func(ctx context.Context) ([]string, error)
+
+func (fn VerifyTokenProviderFunc) VerifyTokens(ctx context.Context) ([]string, error) {
+	return fn(ctx)
+}
+
+type staticTokens []string
+
+func (t staticTokens) VerifyTokens(ctx context.Context) ([]string, error) {
+	return t, nil
+}
+
 func (hooks Webhooks) VerifyToken(queryValues map[string]string) (string, error) {
-	mode := queryValues["hub.mode"]
-	token := queryValues["hub.verify_token"]
+	return hooks.VerifyTokenContext(context.Background(), queryValues)
+}
+
+// Verifies a webhook subscription request and returns its challenge, errors other than
+// provider failures wrap ErrVerifyTokenFailed and ErrInvalidVerifyMode, ErrVerifyTokenMismatch or ErrMissingChallenge
+func (hooks Webhooks) VerifyTokenContext(ctx context.Context, queryValues map[string]string) (string, error) {
+	if queryValues["hub.mode"] != "subscribe" {
+		return "", wrapErr(ErrInvalidVerifyMode, ErrVerifyTokenFailed)
+	}
+
+	var tokens []string
+	if hooks.tokens != nil {
+		var err error
+		if tokens, err = hooks.tokens.VerifyTokens(ctx); err != nil {
+			return "", wrapErr(err, ErrVerifyTokenProvider)
+		}
+	}
+
+	if !matchToken(queryValues["hub.verify_token"], tokens) {
+		return "", wrapErr(ErrVerifyTokenMismatch, ErrVerifyTokenFailed)
+	}
+
 	challenge := queryValues["hub.challenge"]
-	if mode != "subscribe" || token != hooks.token || challenge == "" {
-		return "", ErrVerifyTokenFailed
+	if challenge == "" {
+		return "", wrapErr(ErrMissingChallenge, ErrVerifyTokenFailed)
 	}
 	return challenge, nil
 }
+
+// Compares token to every non-empty active token in constant time
+func matchToken(token string, tokens []string) bool {
+	matched := 0
+	for _, t := range tokens {
+		if t != "" {
+			matched |= subtle.ConstantTimeCompare([]byte(token), []byte(t))
+		}
+	}
+	return matched == 1
+}
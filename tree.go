@@ -0,0 +1,561 @@
+package gometawebhooks
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Payload decoded once into a generic tree, validated against the schema and parsed into an event from the same tree
+type decodedPayload struct {
+	body []byte
+	root interface{}
+
+	// changes and messaging kept undecoded, decoded from body on first use by raw and registered handlers
+	envelope    *payload
+	envelopeErr error
+}
+
+// Decodes body into a generic tree, numbers are kept as json.Number to be validated as written
+func decodePayload(body []byte) (*decodedPayload, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var root interface{}
+	if err := dec.Decode(&root); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid data after top-level value")
+	}
+	return &decodedPayload{body: body, root: root}, nil
+}
+
+// Returns the undecoded messaging item at index of the entry at position
+func (d *decodedPayload) rawMessaging(position, index int) (json.RawMessage, error) {
+	envelope, err := d.undecoded()
+	if err != nil {
+		return nil, err
+	}
+	return envelope.Entry[position].Messaging[index].raw, nil
+}
+
+// Returns the undecoded value of the change at index of the entry at position
+func (d *decodedPayload) rawChange(position, index int) (json.RawMessage, error) {
+	envelope, err := d.undecoded()
+	if err != nil {
+		return nil, err
+	}
+	return envelope.Entry[position].Changes[index].Value.raw, nil
+}
+
+func (d *decodedPayload) undecoded() (*payload, error) {
+	if d.envelope == nil && d.envelopeErr == nil {
+		var envelope payload
+		if d.envelopeErr = json.Unmarshal(d.body, &envelope); d.envelopeErr == nil {
+			d.envelope = &envelope
+		}
+	}
+	return d.envelope, d.envelopeErr
+}
+
+// Item of a payload, kept as its generic tree node when assigned, or undecoded when unmarshalled
+type payloadItem struct {
+	node interface{}
+	raw  json.RawMessage
+}
+
+func (i *payloadItem) UnmarshalJSON(b []byte) error {
+	i.raw = append(json.RawMessage(nil), b...)
+	return nil
+}
+
+var payloadItemType = reflect.TypeFor[payloadItem]()
+
+// Assigns node of a generic tree to the value v points to, as encoding/json decodes the JSON node was decoded from.
+// Keys matching a field exactly take precedence over those matching it case-insensitively.
+func assignTree(node interface{}, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(v)}
+	}
+
+	var path [8]string
+	a := assigner{fieldPath: path[:0]}
+	if err := a.assign(node, rv.Elem()); err != nil {
+		return err
+	}
+	return a.typeErr
+}
+
+type assigner struct {
+	// first type mismatch, assigning carries on past them like encoding/json
+	typeErr error
+
+	// struct and field of the value being assigned, reported by type mismatches
+	structName string
+	fieldPath  []string
+}
+
+func (a *assigner) mismatch(node interface{}, t reflect.Type) {
+	if a.typeErr != nil {
+		return
+	}
+
+	var value string
+	switch node := node.(type) {
+	case string:
+		value = "string"
+	case bool:
+		value = "bool"
+	case json.Number:
+		value = "number " + node.String()
+	case []interface{}:
+		value = "array"
+	case map[string]interface{}:
+		value = "object"
+	}
+
+	a.typeErr = &json.UnmarshalTypeError{
+		Value:  value,
+		Type:   t,
+		Struct: a.structName,
+		Field:  strings.Join(a.fieldPath, "."),
+	}
+}
+
+func (a *assigner) assign(node interface{}, v reflect.Value) error {
+	if v.Type() == payloadItemType {
+		v.Addr().Interface().(*payloadItem).node = node
+		return nil
+	}
+
+	// null sets pointers to nil, otherwise it is passed to unmarshalers
+	if v.Kind() == reflect.Pointer {
+		if node == nil {
+			v.SetZero()
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return a.assign(node, v.Elem())
+	}
+
+	if v.CanAddr() && v.Addr().CanInterface() {
+		switch u := v.Addr().Interface().(type) {
+		case json.Unmarshaler:
+			b, err := json.Marshal(node)
+			if err != nil {
+				return err
+			}
+			return u.UnmarshalJSON(b)
+		case encoding.TextUnmarshaler:
+			if s, ok := node.(string); ok {
+				return u.UnmarshalText([]byte(s))
+			}
+		}
+	}
+
+	if node == nil {
+		switch v.Kind() {
+		case reflect.Interface, reflect.Map, reflect.Slice:
+			v.SetZero()
+		}
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.NumMethod() > 0 {
+			a.mismatch(node, v.Type())
+			return nil
+		}
+		value, err := plainTree(node)
+		if err != nil {
+			a.mismatch(node, v.Type())
+			return nil
+		}
+		v.Set(reflect.ValueOf(value))
+	case reflect.Struct:
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			a.mismatch(node, v.Type())
+			return nil
+		}
+		return a.assignStruct(object, v)
+	case reflect.Map:
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			a.mismatch(node, v.Type())
+			return nil
+		}
+		if v.Type().Key().Kind() != reflect.String {
+			return a.unmarshal(node, v)
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(v.Type(), len(object)))
+		}
+		for key, item := range object {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := a.assign(item, elem); err != nil {
+				return err
+			}
+			v.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), elem)
+		}
+	case reflect.Slice:
+		array, ok := node.([]interface{})
+		if !ok {
+			// e.g. base64 strings into []byte
+			return a.unmarshal(node, v)
+		}
+		slice := reflect.MakeSlice(v.Type(), len(array), len(array))
+		for i, item := range array {
+			if err := a.assign(item, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	case reflect.Array:
+		return a.unmarshal(node, v)
+	case reflect.String:
+		s, ok := node.(string)
+		if !ok {
+			a.mismatch(node, v.Type())
+			return nil
+		}
+		v.SetString(s)
+	case reflect.Bool:
+		b, ok := node.(bool)
+		if !ok {
+			a.mismatch(node, v.Type())
+			return nil
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := node.(json.Number)
+		if !ok {
+			a.mismatch(node, v.Type())
+			return nil
+		}
+		i, err := strconv.ParseInt(n.String(), 10, 64)
+		if err != nil || v.OverflowInt(i) {
+			a.mismatch(node, v.Type())
+			return nil
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, ok := node.(json.Number)
+		if !ok {
+			a.mismatch(node, v.Type())
+			return nil
+		}
+		u, err := strconv.ParseUint(n.String(), 10, 64)
+		if err != nil || v.OverflowUint(u) {
+			a.mismatch(node, v.Type())
+			return nil
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		n, ok := node.(json.Number)
+		if !ok {
+			a.mismatch(node, v.Type())
+			return nil
+		}
+		f, err := strconv.ParseFloat(n.String(), v.Type().Bits())
+		if err != nil || v.OverflowFloat(f) {
+			a.mismatch(node, v.Type())
+			return nil
+		}
+		v.SetFloat(f)
+	default:
+		return a.unmarshal(node, v)
+	}
+	return nil
+}
+
+// Assigns the keys of object matching fields of v, exact matches first
+func (a *assigner) assignStruct(object map[string]interface{}, v reflect.Value) error {
+	fields := cachedTreeFields(v.Type())
+
+	structName, depth := a.structName, len(a.fieldPath)
+	defer func() {
+		a.structName, a.fieldPath = structName, a.fieldPath[:depth]
+	}()
+	a.structName = v.Type().Name()
+
+	var folded []string
+	for key, node := range object {
+		i, ok := fields.byName[key]
+		if !ok {
+			folded = append(folded, key)
+			continue
+		}
+		if err := a.assignField(node, v, fields.list[i], depth); err != nil {
+			return err
+		}
+	}
+
+	sort.Strings(folded)
+	for _, key := range folded {
+		for _, field := range fields.list {
+			// exact matches take precedence
+			if _, exact := object[field.name]; exact || !strings.EqualFold(key, field.name) {
+				continue
+			}
+			if err := a.assignField(object[key], v, field, depth); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	return nil
+}
+
+func (a *assigner) assignField(node interface{}, v reflect.Value, field treeField, depth int) error {
+	// reuses the path of the previous field at the same depth, it is only joined when a mismatch is recorded
+	a.fieldPath = append(a.fieldPath[:depth], field.name)
+
+	fv := v
+	for i, index := range field.index {
+		if i > 0 && fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				if !fv.CanSet() {
+					return errors.New("json: cannot set embedded pointer to unexported struct: " + fv.Type().Elem().String())
+				}
+				fv.Set(reflect.New(fv.Type().Elem()))
+			}
+			fv = fv.Elem()
+		}
+		fv = fv.Field(index)
+	}
+
+	if !field.quoted {
+		return a.assign(node, fv)
+	}
+
+	// ,string fields are encoded as JSON strings, null is ignored
+	if node == nil {
+		return nil
+	}
+	s, ok := node.(string)
+	if !ok {
+		if a.typeErr == nil {
+			a.typeErr = errors.New("json: invalid use of ,string struct tag, trying to unmarshal unquoted value into " + fv.Type().String())
+		}
+		return nil
+	}
+	return a.unmarshalData([]byte(s), fv)
+}
+
+// Assigns node through encoding/json for values the tree doesn't map directly, e.g. arrays and non-string map keys
+func (a *assigner) unmarshal(node interface{}, v reflect.Value) error {
+	data, err := json.Marshal(node)
+	if err != nil {
+		return err
+	}
+	return a.unmarshalData(data, v)
+}
+
+func (a *assigner) unmarshalData(data []byte, v reflect.Value) error {
+	if err := json.Unmarshal(data, v.Addr().Interface()); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			return err
+		}
+		if a.typeErr == nil {
+			a.typeErr = err
+		}
+	}
+	return nil
+}
+
+// Converts json.Number nodes to float64, as encoding/json decodes numbers into interface values
+func plainTree(node interface{}) (interface{}, error) {
+	switch node := node.(type) {
+	case json.Number:
+		return node.Float64()
+	case []interface{}:
+		array := make([]interface{}, len(node))
+		for i, item := range node {
+			var err error
+			if array[i], err = plainTree(item); err != nil {
+				return nil, err
+			}
+		}
+		return array, nil
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(node))
+		for key, item := range node {
+			var err error
+			if object[key], err = plainTree(item); err != nil {
+				return nil, err
+			}
+		}
+		return object, nil
+	}
+	return node, nil
+}
+
+type treeField struct {
+	name   string
+	index  []int
+	typ    reflect.Type
+	tagged bool
+	quoted bool
+}
+
+type treeFields struct {
+	list   []treeField
+	byName map[string]int
+}
+
+var treeFieldCache sync.Map
+
+func cachedTreeFields(t reflect.Type) treeFields {
+	if fields, ok := treeFieldCache.Load(t); ok {
+		return fields.(treeFields)
+	}
+	fields, _ := treeFieldCache.LoadOrStore(t, typeTreeFields(t))
+	return fields.(treeFields)
+}
+
+// Returns the fields of t decoded by encoding/json, including those promoted from embedded structs by the same rules
+func typeTreeFields(t reflect.Type) treeFields {
+	var current []treeField
+	next := []treeField{{typ: t}}
+
+	var count, nextCount map[reflect.Type]int
+	visited := map[reflect.Type]bool{}
+
+	var fields []treeField
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+
+		for _, f := range current {
+			if visited[f.typ] {
+				continue
+			}
+			visited[f.typ] = true
+
+			for i := 0; i < f.typ.NumField(); i++ {
+				sf := f.typ.Field(i)
+				if sf.Anonymous {
+					ft := sf.Type
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+
+				index := make([]int, len(f.index)+1)
+				copy(index, f.index)
+				index[len(f.index)] = i
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					quoted := false
+					if strings.Contains(","+opts+",", ",string,") {
+						switch ft.Kind() {
+						case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+							reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+							reflect.Float32, reflect.Float64, reflect.String:
+							quoted = true
+						}
+					}
+
+					field := treeField{name: name, index: index, typ: ft, tagged: name != "", quoted: quoted}
+					if field.name == "" {
+						field.name = sf.Name
+					}
+					fields = append(fields, field)
+					// annihilated by dominance below when embedded more than once at this depth
+					if count[f.typ] > 1 {
+						fields = append(fields, field)
+					}
+					continue
+				}
+
+				nextCount[ft]++
+				if nextCount[ft] == 1 {
+					next = append(next, treeField{name: ft.Name(), index: index, typ: ft})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].name != fields[j].name {
+			return fields[i].name < fields[j].name
+		}
+		if len(fields[i].index) != len(fields[j].index) {
+			return len(fields[i].index) < len(fields[j].index)
+		}
+		if fields[i].tagged != fields[j].tagged {
+			return fields[i].tagged
+		}
+		return indexLess(fields[i].index, fields[j].index)
+	})
+
+	// the shallowest field of each name wins, preferring tagged ones, and none when still ambiguous
+	out := fields[:0]
+	for advance, i := 0, 0; i < len(fields); i += advance {
+		name := fields[i].name
+		for advance = 1; i+advance < len(fields); advance++ {
+			if fields[i+advance].name != name {
+				break
+			}
+		}
+		if advance == 1 {
+			out = append(out, fields[i])
+			continue
+		}
+		if dominant := fields[i : i+advance]; len(dominant[0].index) < len(dominant[1].index) || dominant[0].tagged != dominant[1].tagged {
+			out = append(out, dominant[0])
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return indexLess(out[i].index, out[j].index)
+	})
+
+	byName := make(map[string]int, len(out))
+	for i, field := range out {
+		byName[field.name] = i
+	}
+	return treeFields{list: out, byName: byName}
+}
+
+func indexLess(a, b []int) bool {
+	for k, i := range a {
+		if k >= len(b) {
+			return false
+		}
+		if i != b[k] {
+			return i < b[k]
+		}
+	}
+	return len(a) < len(b)
+}
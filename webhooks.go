@@ -4,6 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"golang.org/x/sync/semaphore"
 )
 
 var (
@@ -12,16 +17,64 @@ var (
 
 // Webhooks instance contains all methods needed to process object events
 type Webhooks struct {
-	token  string
-	secret string
+	tokens  VerifyTokenProvider
+	secrets SecretProvider
+
+	headerSigName   string
+	legacySignature bool
+	maxBodyBytes    int64
 
-	headerSigName string
+	schema        *jsonschema.Schema
+	objectSchemas map[Object]*jsonschema.Schema
 
 	instagramMessageHandler       InstagramMessageHandler
 	instagramPostbackHandler      InstagramPostbackHandler
 	instagramReferralHandler      InstagramReferralHandler
+	instagramReactionHandler      InstagramReactionHandler
+	instagramSeenHandler          InstagramSeenHandler
 	instagramMentionHandler       InstagramMentionHandler
 	instagramStoryInsightsHandler InstagramStoryInsightsHandler
+	instagramCommentHandler       InstagramCommentHandler
+	instagramLiveCommentHandler   InstagramLiveCommentHandler
+
+	pageMessageHandler  PageMessageHandler
+	pagePostbackHandler PagePostbackHandler
+	pageReferralHandler PageReferralHandler
+	pageReactionHandler PageReactionHandler
+	pageSeenHandler     PageSeenHandler
+
+	whatsAppMessageHandler WhatsAppMessageHandler
+	whatsAppStatusHandler  WhatsAppStatusHandler
+
+	changeFields   map[changeField]ChangeFieldHandler
+	messagingTypes map[string]MessagingTypeHandler
+	// registered messaging type keys in order, see Webhooks.messagingTypeKey
+	messagingKeys []string
+
+	rawChangeHandler    RawChangeHandler
+	rawMessagingHandler RawMessagingHandler
+
+	dedupStore  DedupStore
+	middlewares []Middleware
+
+	logger      *slog.Logger
+	logPayloads bool
+
+	recoverPanics   bool
+	continueOnError bool
+
+	maxConcurrency  int64
+	sharedSemaphore *semaphore.Weighted
+
+	orderedConversations bool
+
+	maxEventAge     time.Duration
+	maxEventSkew    time.Duration
+	flagStaleEvents bool
+	replayCache     DedupStore
+
+	defaultHandlerTimeout time.Duration
+	handlerTimeouts       map[string]time.Duration
 
 	ignoreEchoMessages bool
 }
@@ -34,7 +87,9 @@ var _ WebhooksHandler = (*Webhooks)(nil)
 
 // Creates and returns a webhooks instance
 func New(options ...Option) (*Webhooks, error) {
-	hooks := &Webhooks{}
+	hooks := &Webhooks{
+		recoverPanics: true,
+	}
 
 	for _, opt := range options {
 		if err := opt(hooks); err != nil {
@@ -52,7 +107,3 @@ func New(options ...Option) (*Webhooks, error) {
 func wrapErr(err, target error) error {
 	return fmt.Errorf("%w: %w", err, target)
 }
-
-// func unixTime(timeMs int64) time.Time {
-// 	return time.Unix(0, timeMs*int64(time.Millisecond))
-// }
@@ -0,0 +1,209 @@
+package gometawebhooks
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrWhatsAppMessageHandlerNotDefined = errors.New("whatsapp message handler not defined")
+	ErrWhatsAppStatusHandlerNotDefined  = errors.New("whatsapp status handler not defined")
+)
+
+type WhatsAppMetadata struct {
+	DisplayPhoneNumber string `json:"display_phone_number,omitempty"`
+	PhoneNumberId      string `json:"phone_number_id,omitempty"`
+}
+
+type WhatsAppProfile struct {
+	Name string `json:"name,omitempty"`
+}
+
+type WhatsAppContact struct {
+	WaId    string          `json:"wa_id,omitempty"`
+	Profile WhatsAppProfile `json:"profile,omitempty"`
+}
+
+type WhatsAppErrorData struct {
+	Details string `json:"details,omitempty"`
+}
+
+type WhatsAppError struct {
+	Code      int                `json:"code,omitempty"`
+	Title     string             `json:"title,omitempty"`
+	Message   string             `json:"message,omitempty"`
+	ErrorData *WhatsAppErrorData `json:"error_data,omitempty"`
+}
+
+type WhatsAppContext struct {
+	From string `json:"from,omitempty"`
+	Id   string `json:"id,omitempty"`
+}
+
+type WhatsAppText struct {
+	Body string `json:"body,omitempty"`
+}
+
+type WhatsAppMedia struct {
+	Id       string `json:"id,omitempty"`
+	MimeType string `json:"mime_type,omitempty"`
+	Sha256   string `json:"sha256,omitempty"`
+	Caption  string `json:"caption,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	Voice    bool   `json:"voice,omitempty"`
+}
+
+type WhatsAppLocation struct {
+	Latitude  float64 `json:"latitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
+	Name      string  `json:"name,omitempty"`
+	Address   string  `json:"address,omitempty"`
+	URL       string  `json:"url,omitempty"`
+}
+
+type WhatsAppReply struct {
+	Id          string `json:"id,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type WhatsAppInteractive struct {
+	Type        string         `json:"type,omitempty"`
+	ButtonReply *WhatsAppReply `json:"button_reply,omitempty"`
+	ListReply   *WhatsAppReply `json:"list_reply,omitempty"`
+}
+
+type WhatsAppReaction struct {
+	MessageId string `json:"message_id,omitempty"`
+	Emoji     string `json:"emoji,omitempty"`
+}
+
+// https://developers.facebook.com/docs/whatsapp/cloud-api/webhooks/components#messages-object
+type WhatsAppMessage struct {
+	Id        string `json:"id,omitempty"`
+	From      string `json:"from,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Type      string `json:"type,omitempty"`
+
+	Context     *WhatsAppContext     `json:"context,omitempty"`
+	Text        *WhatsAppText        `json:"text,omitempty"`
+	Image       *WhatsAppMedia       `json:"image,omitempty"`
+	Audio       *WhatsAppMedia       `json:"audio,omitempty"`
+	Document    *WhatsAppMedia       `json:"document,omitempty"`
+	Location    *WhatsAppLocation    `json:"location,omitempty"`
+	Interactive *WhatsAppInteractive `json:"interactive,omitempty"`
+	Reaction    *WhatsAppReaction    `json:"reaction,omitempty"`
+	Errors      []WhatsAppError      `json:"errors,omitempty"`
+}
+
+type WhatsAppConversationOrigin struct {
+	Type string `json:"type,omitempty"`
+}
+
+type WhatsAppConversation struct {
+	Id                  string                      `json:"id,omitempty"`
+	ExpirationTimestamp string                      `json:"expiration_timestamp,omitempty"`
+	Origin              *WhatsAppConversationOrigin `json:"origin,omitempty"`
+}
+
+type WhatsAppPricing struct {
+	Billable     bool   `json:"billable,omitempty"`
+	PricingModel string `json:"pricing_model,omitempty"`
+	Category     string `json:"category,omitempty"`
+}
+
+// https://developers.facebook.com/docs/whatsapp/cloud-api/webhooks/components#statuses-object
+type WhatsAppStatus struct {
+	Id           string                `json:"id,omitempty"`
+	Status       string                `json:"status,omitempty"`
+	Timestamp    string                `json:"timestamp,omitempty"`
+	RecipientId  string                `json:"recipient_id,omitempty"`
+	Conversation *WhatsAppConversation `json:"conversation,omitempty"`
+	Pricing      *WhatsAppPricing      `json:"pricing,omitempty"`
+	Errors       []WhatsAppError       `json:"errors,omitempty"`
+}
+
+// https://developers.facebook.com/docs/whatsapp/cloud-api/webhooks/components#value-object
+type WhatsAppMessages struct {
+	MessagingProduct string            `json:"messaging_product,omitempty"`
+	Metadata         WhatsAppMetadata  `json:"metadata,omitempty"`
+	Contacts         []WhatsAppContact `json:"contacts,omitempty"`
+	Messages         []WhatsAppMessage `json:"messages,omitempty"`
+	Statuses         []WhatsAppStatus  `json:"statuses,omitempty"`
+	Errors           []WhatsAppError   `json:"errors,omitempty"`
+}
+
+type WhatsAppMessageHandler interface {
+	WhatsAppMessage(ctx context.Context, object Object, entry Entry, value WhatsAppMessages, message WhatsAppMessage) error
+}
+
+type WhatsAppStatusHandler interface {
+	WhatsAppStatus(ctx context.Context, object Object, entry Entry, value WhatsAppMessages, status WhatsAppStatus) error
+}
+
+type WhatsAppHandler interface {
+	WhatsAppMessageHandler
+	WhatsAppStatusHandler
+}
+
+// Returns the value of a WhatsApp messages change dispatched per message and status, unless a registered field handles it
+func (h Webhooks) whatsAppValue(object Object, change Change) (WhatsAppMessages, bool) {
+	value, ok := change.Value.(WhatsAppMessages)
+	if !ok {
+		return value, false
+	}
+	_, registered := h.changeFields[changeField{object, change.Field}]
+	return value, !registered
+}
+
+// Dispatches every message and status of the change at index of entry as its own item
+func (h Webhooks) whatsAppMessages(ctx context.Context, b *batch, position int, object Object, entry Entry, index int, change Change, value WhatsAppMessages) error {
+	if len(value.Messages) > 0 && h.whatsAppMessageHandler == nil {
+		return b.fail(position, entry, index, change, ErrWhatsAppMessageHandlerNotDefined)
+	}
+
+	if len(value.Statuses) > 0 && h.whatsAppStatusHandler == nil {
+		return b.fail(position, entry, index, change, ErrWhatsAppStatusHandlerNotDefined)
+	}
+
+	if len(value.Messages)+len(value.Statuses) == 0 {
+		return nil
+	}
+
+	g, ctx := b.group(ctx)
+	g.SetLimit(len(value.Messages) + len(value.Statuses))
+
+	dispatch := func(i int, item interface{}, fn func(context.Context) error) bool {
+		p, err := b.acquire(ctx)
+		if err != nil {
+			g.Go(func() error { return err })
+			return false
+		}
+
+		g.Go(func() error {
+			defer p.release()
+
+			err := h.dispatch(ctx, p, object, entry, item, fn)
+			return b.failAt(position, entry, index, i, item, err)
+		})
+		return true
+	}
+
+	for i, message := range value.Messages {
+		if !dispatch(i, message, func(ctx context.Context) error {
+			return h.whatsAppMessageHandler.WhatsAppMessage(ctx, object, entry, value, message)
+		}) {
+			return g.Wait()
+		}
+	}
+
+	for i, status := range value.Statuses {
+		if !dispatch(i, status, func(ctx context.Context) error {
+			return h.whatsAppStatusHandler.WhatsAppStatus(ctx, object, entry, value, status)
+		}) {
+			return g.Wait()
+		}
+	}
+
+	return g.Wait()
+}